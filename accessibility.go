@@ -0,0 +1,46 @@
+package rodutils
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// AccessibilityTree returns the root node of the full accessibility tree of the page.
+// The descendants are referenced by the ChildIDs of each node and can be fetched with proto.AccessibilityGetChildAXNodes.
+// It returns the root node and an error, if any.
+// If the tree is empty, it returns an error.
+func AccessibilityTree(page *rod.Page) (*proto.AccessibilityAXNode, error) {
+	if page == nil {
+		return nil, fmt.Errorf("rod.Page is nil")
+	}
+	res, err := proto.AccessibilityGetFullAXTree{}.Call(page)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get accessibility tree: %v", err)
+	}
+	for _, node := range res.Nodes {
+		if node.ParentID == "" {
+			return node, nil
+		}
+	}
+	return nil, errors.New("accessibility tree is empty")
+}
+
+// AccessibleName returns the accessible name of the element as computed by the browser, such as the label of a form field.
+// It returns an empty string if the element has no accessible name.
+// It returns the name and an error, if any.
+func AccessibleName(e *rod.Element) (string, error) {
+	if e == nil {
+		return "", errors.New("rod.Element is nil")
+	}
+	res, err := proto.AccessibilityGetPartialAXTree{ObjectID: e.Object.ObjectID}.Call(e)
+	if err != nil {
+		return "", fmt.Errorf("failed to get accessibility node: %v", err)
+	}
+	if len(res.Nodes) == 0 || res.Nodes[0].Name == nil {
+		return "", nil
+	}
+	return res.Nodes[0].Name.Value.Str(), nil
+}
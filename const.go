@@ -5,3 +5,4 @@ import "time"
 const DefaultScreenshotPath = "error-screenshot"
 const DefaultTimeoutDuration = 30 * time.Second
 const DefaultStableDuration = 500 * time.Millisecond
+const DefaultPollInterval = 100 * time.Millisecond
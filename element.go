@@ -1,11 +1,18 @@
 package rodutils
 
 import (
+	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/cdp"
 	"github.com/go-rod/rod/lib/proto"
 )
 
@@ -121,7 +128,9 @@ func Click(e *rod.Element) error {
 		return errors.New("failed to wait for element to be enabled")
 	}
 
-	err = e.Click(proto.InputMouseButtonLeft, 1)
+	err = withMouse(e.Page(), func() error {
+		return e.Click(proto.InputMouseButtonLeft, 1)
+	})
 	if err != nil {
 		return fmt.Errorf("failed to click: %v", err)
 	}
@@ -179,3 +188,727 @@ func Attribute(e *rod.Element, name string) (*string, error) {
 	}
 	return attr, nil
 }
+
+// CanvasDataURL returns the content of the canvas element as a base64 PNG data URL.
+// It returns the data URL and an error, if any.
+// If the element is not a canvas or the data URL cannot be retrieved, it returns an error.
+func CanvasDataURL(e *rod.Element) (string, error) {
+	if e == nil {
+		return "", errors.New("rod.Element is nil")
+	}
+	if err := requireTag(e, "canvas"); err != nil {
+		return "", err
+	}
+	res, err := e.Eval(`() => this.toDataURL('image/png')`)
+	if err != nil {
+		return "", fmt.Errorf("failed to get canvas data URL: %v", err)
+	}
+	return res.Value.Str(), nil
+}
+
+// SaveCanvasPNG saves the content of the canvas element as a PNG file.
+// It creates the parent directory of the path if it does not exist.
+// It returns an error if the canvas cannot be read or the file cannot be written.
+func SaveCanvasPNG(e *rod.Element, path string) error {
+	dataURL, err := CanvasDataURL(e)
+	if err != nil {
+		return err
+	}
+	_, encoded, found := strings.Cut(dataURL, ",")
+	if !found {
+		return fmt.Errorf("unexpected canvas data URL format: %.32s", dataURL)
+	}
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return fmt.Errorf("failed to decode canvas data URL: %v", err)
+	}
+	return writeFile(path, data)
+}
+
+// requireTag checks that the element has the given tag name.
+// It returns an error if the tag name cannot be retrieved or does not match.
+func requireTag(e *rod.Element, tag string) error {
+	res, err := e.Eval(`() => this.tagName`)
+	if err != nil {
+		return fmt.Errorf("failed to get tag name: %v", err)
+	}
+	if actual := res.Value.Str(); !strings.EqualFold(actual, tag) {
+		return fmt.Errorf("element is not a <%s>: <%s>", tag, strings.ToLower(actual))
+	}
+	return nil
+}
+
+// SelectedOptions returns the values of the currently selected options of the select element.
+// It supports multi-selects and returns an empty slice if nothing is selected.
+// If the element is not a select or the options cannot be read, it returns an error.
+func SelectedOptions(e *rod.Element) ([]string, error) {
+	return selectedOptions(e, "value")
+}
+
+// SelectedTexts returns the labels of the currently selected options of the select element.
+// It supports multi-selects and returns an empty slice if nothing is selected.
+// If the element is not a select or the options cannot be read, it returns an error.
+func SelectedTexts(e *rod.Element) ([]string, error) {
+	return selectedOptions(e, "text")
+}
+
+// selectedOptions returns the given property of each selected option of the select element.
+func selectedOptions(e *rod.Element, prop string) ([]string, error) {
+	if e == nil {
+		return nil, errors.New("rod.Element is nil")
+	}
+	if err := requireTag(e, "select"); err != nil {
+		return nil, err
+	}
+	res, err := e.Eval(`(prop) => Array.from(this.selectedOptions, o => o[prop])`, prop)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get selected options: %v", err)
+	}
+	values := []string{}
+	if err := res.Value.Unmarshal(&values); err != nil {
+		return nil, fmt.Errorf("failed to decode selected options: %v", err)
+	}
+	return values, nil
+}
+
+// TypeHuman types the text into the element one character at a time with a randomized delay between keystrokes.
+// Each character is dispatched as a key down and key up event, so per-key handlers such as autocomplete fire.
+// It returns an error if the element is not writable or if dispatching a key fails.
+func TypeHuman(e *rod.Element, text string, minDelay, maxDelay time.Duration) error {
+	if e == nil {
+		return errors.New("rod.Element is nil")
+	}
+	err := e.WaitWritable()
+	if err != nil {
+		return errors.New("failed to wait for element to be writable")
+	}
+	err = e.Focus()
+	if err != nil {
+		return fmt.Errorf("failed to focus element: %v", err)
+	}
+
+	page := e.Page()
+	for i, r := range text {
+		if i > 0 {
+			time.Sleep(randomDelay(minDelay, maxDelay))
+		}
+		key := string(r)
+		err = proto.InputDispatchKeyEvent{Type: proto.InputDispatchKeyEventTypeKeyDown, Key: key, Text: key}.Call(page)
+		if err != nil {
+			return fmt.Errorf("failed to type character: %q\n%v", key, err)
+		}
+		err = proto.InputDispatchKeyEvent{Type: proto.InputDispatchKeyEventTypeKeyUp, Key: key}.Call(page)
+		if err != nil {
+			return fmt.Errorf("failed to type character: %q\n%v", key, err)
+		}
+	}
+
+	return nil
+}
+
+// WaitAnimationsDone waits for all animations on the element to finish, using the Web Animations API.
+// It resolves immediately if the browser does not support Element.getAnimations.
+// It returns an error if the animations have not finished within opts.Timeout.
+func WaitAnimationsDone(e *rod.Element, opts *RodOptions) error {
+	if e == nil {
+		return errors.New("rod.Element is nil")
+	}
+	if opts == nil {
+		opts = DefaultRodOptions()
+	}
+
+	// Timeout context
+	ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
+	defer cancel()
+
+	err := pollUntil(ctx, opts.RetryDelay, func() (bool, error) {
+		res, err := e.Context(ctx).Eval(`() => typeof this.getAnimations !== 'function' ||
+			this.getAnimations().every(a => a.playState === 'finished' || a.playState === 'idle')`)
+		if err != nil {
+			return false, fmt.Errorf("failed to get animations: %v", err)
+		}
+		return res.Value.Bool(), nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to wait for animations to finish: %w", err)
+	}
+	return nil
+}
+
+// transitionEndJS resolves once a transitionend or animationend event fires on the element itself,
+// or immediately if no animation or transition is running on it.
+const transitionEndJS = `() => new Promise(resolve => {
+	const running = typeof this.getAnimations === 'function' &&
+		this.getAnimations().some(a => a.playState === 'running' || a.playState === 'pending');
+	if (!running) return resolve();
+	const types = ['transitionend', 'transitioncancel', 'animationend', 'animationcancel'];
+	const done = (event) => {
+		if (event.target !== this) return;
+		types.forEach(type => this.removeEventListener(type, done));
+		resolve();
+	};
+	types.forEach(type => this.addEventListener(type, done));
+})`
+
+// WaitTransitionEnd waits for the next transitionend or animationend event of the element, without polling.
+// Cancelled transitions and animations also end the wait, and events bubbling up from descendants are ignored.
+// It returns immediately if no transition or animation is in progress.
+// It returns an error if the event does not fire within opts.Timeout.
+func WaitTransitionEnd(e *rod.Element, opts *RodOptions) error {
+	if e == nil {
+		return errors.New("rod.Element is nil")
+	}
+	if opts == nil {
+		opts = DefaultRodOptions()
+	}
+
+	// Timeout context
+	ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
+	defer cancel()
+
+	if _, err := e.Context(ctx).Eval(transitionEndJS); err != nil {
+		return fmt.Errorf("failed to wait for transition to end: %w", err)
+	}
+	return nil
+}
+
+// WaitChild waits for a descendant of the parent element matching the selector to be present.
+// It scopes the wait to the parent's subtree, so matches elsewhere in the page are ignored.
+// It returns the descendant and an error, if any.
+// If no descendant appears within opts.Timeout, it returns an error.
+func WaitChild(parent *rod.Element, childSelector string, opts *RodOptions) (*rod.Element, error) {
+	if parent == nil {
+		return nil, errors.New("rod.Element is nil")
+	}
+	if opts == nil {
+		opts = DefaultRodOptions()
+	}
+	if err := opts.validateSelector(childSelector); err != nil {
+		return nil, err
+	}
+
+	// Timeout context
+	ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
+	defer cancel()
+
+	var child *rod.Element
+	err := pollUntil(ctx, opts.RetryDelay, func() (bool, error) {
+		has, elem, err := parent.Context(ctx).Has(childSelector)
+		if err != nil {
+			return false, fmt.Errorf("failed to check element existence: %s\n%v", childSelector, err)
+		}
+		child = elem
+		return has, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("child element not found: %s\n%w", childSelector, err)
+	}
+
+	// Detach the element from the timeout context so it stays usable
+	return child.Context(parent.GetContext()), nil
+}
+
+// IsClickable reports whether a click at the center of the element would reach the element or one of its descendants.
+// It returns false if another element, such as an overlay, covers the center or if the center is outside the viewport.
+// It returns an error if the check cannot be evaluated.
+func IsClickable(e *rod.Element) (bool, error) {
+	if e == nil {
+		return false, errors.New("rod.Element is nil")
+	}
+	res, err := e.Eval(`() => {
+		const rect = this.getBoundingClientRect();
+		const hit = document.elementFromPoint(rect.left + rect.width / 2, rect.top + rect.height / 2);
+		return hit !== null && this.contains(hit);
+	}`)
+	if err != nil {
+		return false, fmt.Errorf("failed to check whether element is clickable: %v", err)
+	}
+	return res.Value.Bool(), nil
+}
+
+// OwnText returns the text of the element's direct text nodes, excluding the text of its child elements.
+// Each text node is trimmed, and the non-empty ones are joined with a space.
+// It returns an error if the text cannot be retrieved.
+func OwnText(e *rod.Element) (string, error) {
+	if e == nil {
+		return "", errors.New("rod.Element is nil")
+	}
+	res, err := e.Eval(`() => Array.from(this.childNodes)
+		.filter(n => n.nodeType === Node.TEXT_NODE)
+		.map(n => n.nodeValue.trim())
+		.filter(t => t !== '')
+		.join(' ')`)
+	if err != nil {
+		return "", fmt.Errorf("failed to get own text: %v", err)
+	}
+	return res.Value.Str(), nil
+}
+
+// carouselPositionJS returns a fingerprint of the carousel's scroll position.
+// It combines the scroll offset with the position of the first child, so both scroll-based and transform-based carousels are covered.
+const carouselPositionJS = `() => {
+	const child = this.firstElementChild;
+	return this.scrollLeft + ':' + (child ? child.getBoundingClientRect().left : 0);
+}`
+
+// ScrollCarousel advances a horizontal carousel by dragging the mouse across the element steps times.
+// The direction "right" reveals the next items by dragging leftward, and "left" reveals the previous items.
+// It stops early once a drag no longer moves the carousel.
+// It returns an error if the direction is invalid or if a mouse operation fails.
+func ScrollCarousel(e *rod.Element, direction string, steps int) error {
+	if e == nil {
+		return errors.New("rod.Element is nil")
+	}
+	var sign float64
+	switch direction {
+	case "right":
+		sign = -1
+	case "left":
+		sign = 1
+	default:
+		return fmt.Errorf("invalid carousel direction: %s", direction)
+	}
+	err := e.ScrollIntoView()
+	if err != nil {
+		return fmt.Errorf("failed to scroll element into view: %v", err)
+	}
+
+	mouse := e.Page().Mouse
+	for i := 0; i < steps; i++ {
+		res, err := e.Eval(`() => { const r = this.getBoundingClientRect(); return { x: r.left + r.width / 2, y: r.top + r.height / 2, w: r.width }; }`)
+		if err != nil {
+			return fmt.Errorf("failed to get carousel bounds: %v", err)
+		}
+		before, err := e.Eval(carouselPositionJS)
+		if err != nil {
+			return fmt.Errorf("failed to get carousel position: %v", err)
+		}
+
+		center := proto.Point{X: res.Value.Get("x").Num(), Y: res.Value.Get("y").Num()}
+		distance := res.Value.Get("w").Num() * 0.35
+		err = withMouse(e.Page(), func() error {
+			if err := mouse.MoveTo(proto.Point{X: center.X - sign*distance, Y: center.Y}); err != nil {
+				return fmt.Errorf("failed to move mouse: %v", err)
+			}
+			if err := mouse.Down(proto.InputMouseButtonLeft, 1); err != nil {
+				return fmt.Errorf("failed to press mouse: %v", err)
+			}
+			if err := mouse.MoveLinear(proto.Point{X: center.X + sign*distance, Y: center.Y}, 10); err != nil {
+				return fmt.Errorf("failed to drag carousel: %v", err)
+			}
+			if err := mouse.Up(proto.InputMouseButtonLeft, 1); err != nil {
+				return fmt.Errorf("failed to release mouse: %v", err)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		// Let the carousel settle before comparing positions
+		time.Sleep(DefaultStableDuration)
+		after, err := e.Eval(carouselPositionJS)
+		if err != nil {
+			return fmt.Errorf("failed to get carousel position: %v", err)
+		}
+		if after.Value.Str() == before.Value.Str() {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// IsAttached reports whether the element is still connected to the document.
+// A reference whose node or JavaScript context no longer exists, for example after a re-render or navigation, is reported as detached.
+// It returns an error if the check fails for another reason.
+func IsAttached(e *rod.Element) (bool, error) {
+	if e == nil {
+		return false, errors.New("rod.Element is nil")
+	}
+	res, err := e.Eval(`() => this.isConnected`)
+	if errors.Is(err, &rod.ObjectNotFoundError{}) || errors.Is(err, cdp.ErrObjNotFound) ||
+		errors.Is(err, cdp.ErrCtxNotFound) || errors.Is(err, cdp.ErrCtxDestroyed) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to check whether element is attached: %v", err)
+	}
+	return res.Value.Bool(), nil
+}
+
+// Refresh returns the element if it is still attached, or re-resolves the selector on the page otherwise.
+// It gives callers holding a reference across a re-render a clean way to recover from stale elements.
+// It returns an error if the attachment cannot be checked or the selector no longer matches.
+func Refresh(e *rod.Element, page *rod.Page, selector string) (*rod.Element, error) {
+	if page == nil {
+		return nil, errors.New("rod.Page is nil")
+	}
+	if e != nil {
+		attached, err := IsAttached(e)
+		if err != nil {
+			return nil, err
+		}
+		if attached {
+			return e, nil
+		}
+	}
+	has, elem, err := page.Has(selector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check element existence: %s\n%v", selector, err)
+	}
+	if !has {
+		return nil, fmt.Errorf("element not found: %s", selector)
+	}
+	return elem, nil
+}
+
+// Option describes an option of a select element.
+type Option struct {
+	Value    string `json:"value"`    // Value submitted with the form
+	Text     string `json:"text"`     // Label shown to the user
+	Disabled bool   `json:"disabled"` // Whether the option can be selected
+}
+
+// SelectOptions returns all options of the select element in document order.
+// It returns the options and an error, if any.
+// If the element is not a select or the options cannot be read, it returns an error.
+func SelectOptions(e *rod.Element) ([]Option, error) {
+	if e == nil {
+		return nil, errors.New("rod.Element is nil")
+	}
+	if err := requireTag(e, "select"); err != nil {
+		return nil, err
+	}
+	res, err := e.Eval(`() => Array.from(this.options, o => ({ value: o.value, text: o.text, disabled: o.disabled }))`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get options: %v", err)
+	}
+	options := []Option{}
+	if err := res.Value.Unmarshal(&options); err != nil {
+		return nil, fmt.Errorf("failed to decode options: %v", err)
+	}
+	return options, nil
+}
+
+// WaitValueStable waits for the value of the element to stay unchanged for quiet.
+// It reads the value of form fields and the text content of other elements.
+// It returns the settled value and an error, if any.
+// If the value keeps changing past opts.Timeout, it returns the last seen value along with an error.
+func WaitValueStable(e *rod.Element, quiet time.Duration, opts *RodOptions) (string, error) {
+	if e == nil {
+		return "", errors.New("rod.Element is nil")
+	}
+	if opts == nil {
+		opts = DefaultRodOptions()
+	}
+
+	// Timeout context
+	ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
+	defer cancel()
+
+	var value string
+	var read bool
+	var unchangedSince time.Time
+	err := pollUntil(ctx, min(opts.RetryDelay, quiet), func() (bool, error) {
+		res, err := e.Context(ctx).Eval(`() => typeof this.value === 'string' ? this.value : this.textContent`)
+		if err != nil {
+			return false, fmt.Errorf("failed to get value: %v", err)
+		}
+		current := res.Value.Str()
+		if !read || current != value {
+			value, read = current, true
+			unchangedSince = time.Now()
+			return false, nil
+		}
+		return time.Since(unchangedSince) >= quiet, nil
+	})
+	if err != nil {
+		return value, fmt.Errorf("value did not stabilize (last seen: %q)\n%w", value, err)
+	}
+	return value, nil
+}
+
+// DataAttributes returns the data-* attributes of the element keyed by their dataset name, so data-user-id becomes userId.
+// It returns the attributes and an error, if any.
+func DataAttributes(e *rod.Element) (map[string]string, error) {
+	return dataAttributes(e, `() => Object.assign({}, this.dataset)`)
+}
+
+// DataAttributesKebab is like DataAttributes, but keeps the attribute names as written without the data- prefix,
+// so data-user-id becomes user-id.
+func DataAttributesKebab(e *rod.Element) (map[string]string, error) {
+	return dataAttributes(e, `() => Object.fromEntries(Array.from(this.attributes)
+		.filter(a => a.name.startsWith('data-'))
+		.map(a => [a.name.slice(5), a.value]))`)
+}
+
+// dataAttributes evaluates js on the element and decodes the returned object into a map.
+func dataAttributes(e *rod.Element, js string) (map[string]string, error) {
+	if e == nil {
+		return nil, errors.New("rod.Element is nil")
+	}
+	res, err := e.Eval(js)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get data attributes: %v", err)
+	}
+	attrs := map[string]string{}
+	if err := res.Value.Unmarshal(&attrs); err != nil {
+		return nil, fmt.Errorf("failed to decode data attributes: %v", err)
+	}
+	return attrs, nil
+}
+
+// ScrollToCenter scrolls the element into the center of the viewport, so sticky headers and footers do not cover it.
+// It waits for the scroll to settle before returning.
+// It returns an error if scrolling fails.
+func ScrollToCenter(e *rod.Element) error {
+	return ScrollToCenterBehavior(e, "auto")
+}
+
+// ScrollToCenterBehavior is like ScrollToCenter, but uses the given scroll behavior, either "auto" or "smooth".
+func ScrollToCenterBehavior(e *rod.Element, behavior string) error {
+	if e == nil {
+		return errors.New("rod.Element is nil")
+	}
+	if behavior != "auto" && behavior != "smooth" {
+		return fmt.Errorf("invalid scroll behavior: %s", behavior)
+	}
+	_, err := e.Eval(`(behavior) => this.scrollIntoView({ block: 'center', inline: 'center', behavior })`, behavior)
+	if err != nil {
+		return fmt.Errorf("failed to scroll element to center: %v", err)
+	}
+	err = e.WaitStableRAF()
+	if err != nil {
+		return fmt.Errorf("failed to wait for scroll to settle: %v", err)
+	}
+	return nil
+}
+
+// WaitChildCount waits for the parent element to contain at least min descendants matching the selector.
+// It scopes the count to the parent's subtree, which matters for nested grids and lists.
+// It returns an error if the count is not reached within opts.Timeout, including the last seen count.
+func WaitChildCount(parent *rod.Element, childSelector string, min int, opts *RodOptions) error {
+	if parent == nil {
+		return errors.New("rod.Element is nil")
+	}
+	if opts == nil {
+		opts = DefaultRodOptions()
+	}
+	if err := opts.validateSelector(childSelector); err != nil {
+		return err
+	}
+
+	// Timeout context
+	ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
+	defer cancel()
+
+	count := 0
+	err := pollUntil(ctx, opts.RetryDelay, func() (bool, error) {
+		res, err := parent.Context(ctx).Eval(`(selector) => this.querySelectorAll(selector).length`, childSelector)
+		if err != nil {
+			return false, fmt.Errorf("failed to count child elements: %s\n%v", childSelector, err)
+		}
+		count = res.Value.Int()
+		return count >= min, nil
+	})
+	if err != nil {
+		return fmt.Errorf("child elements did not reach %d (last seen: %d): %s\n%w", min, count, childSelector, err)
+	}
+	return nil
+}
+
+// Paste writes the text to the clipboard and pastes it into the element with Ctrl+V.
+// Unlike Input, this fires a real paste event, so rich text editors and fields that sanitize typed input treat it as pasted content.
+// It requires clipboard access for the page's origin, for example via GrantPermissions(page, "clipboardReadWrite", "clipboardSanitizedWrite").
+// It returns an error if the clipboard cannot be written or the paste fails.
+func Paste(e *rod.Element, text string) error {
+	if e == nil {
+		return errors.New("rod.Element is nil")
+	}
+	err := e.Focus()
+	if err != nil {
+		return fmt.Errorf("failed to focus element: %v", err)
+	}
+
+	page := e.Page()
+	_, err = page.Eval(`(text) => navigator.clipboard.writeText(text)`, text)
+	if err != nil {
+		return fmt.Errorf("failed to write clipboard, make sure clipboard permissions are granted: %v", err)
+	}
+
+	// The paste command performs the paste regardless of the platform's shortcut
+	const ctrl = 2
+	err = proto.InputDispatchKeyEvent{
+		Type:                  proto.InputDispatchKeyEventTypeKeyDown,
+		Modifiers:             ctrl,
+		Key:                   "v",
+		Code:                  "KeyV",
+		WindowsVirtualKeyCode: 'V',
+		Commands:              []string{"paste"},
+	}.Call(page)
+	if err != nil {
+		return fmt.Errorf("failed to paste: %v", err)
+	}
+	err = proto.InputDispatchKeyEvent{
+		Type:                  proto.InputDispatchKeyEventTypeKeyUp,
+		Modifiers:             ctrl,
+		Key:                   "v",
+		Code:                  "KeyV",
+		WindowsVirtualKeyCode: 'V',
+	}.Call(page)
+	if err != nil {
+		return fmt.Errorf("failed to paste: %v", err)
+	}
+	return nil
+}
+
+// rgbaPattern matches computed colors in the rgb() and rgba() notations, with comma or space separated components.
+var rgbaPattern = regexp.MustCompile(`^rgba?\(\s*([\d.]+)[\s,]+([\d.]+)[\s,]+([\d.]+)(?:\s*[,/]\s*([\d.]+%?))?\s*\)$`)
+
+// ElementColor returns the computed color of the CSS property of the element, such as color or background-color.
+// The alpha component is scaled to 0-255, and is 255 for opaque colors.
+// It returns the color components and an error, if any.
+// If the computed value is not an rgb() or rgba() color, such as a gradient, it returns an error.
+func ElementColor(e *rod.Element, property string) (r, g, b, a uint8, err error) {
+	if e == nil {
+		return 0, 0, 0, 0, errors.New("rod.Element is nil")
+	}
+	res, err := e.Eval(`(property) => getComputedStyle(this).getPropertyValue(property)`, property)
+	if err != nil {
+		return 0, 0, 0, 0, fmt.Errorf("failed to get computed style: %s\n%v", property, err)
+	}
+	return parseRGBA(strings.TrimSpace(res.Value.Str()))
+}
+
+// parseRGBA parses a computed color in the rgb() or rgba() notation into its components.
+// It returns an error for any other format.
+func parseRGBA(value string) (r, g, b, a uint8, err error) {
+	m := rgbaPattern.FindStringSubmatch(value)
+	if m == nil {
+		return 0, 0, 0, 0, fmt.Errorf("unexpected color format: %q", value)
+	}
+	var rgb [3]uint8
+	for i := range rgb {
+		n, err := strconv.ParseFloat(m[i+1], 64)
+		if err != nil || n > 255 {
+			return 0, 0, 0, 0, fmt.Errorf("unexpected color format: %q", value)
+		}
+		rgb[i] = uint8(math.Round(n))
+	}
+
+	a = 255
+	if m[4] != "" {
+		alpha, percent := strings.CutSuffix(m[4], "%")
+		n, err := strconv.ParseFloat(alpha, 64)
+		if percent {
+			n /= 100
+		}
+		if err != nil || n > 1 {
+			return 0, 0, 0, 0, fmt.Errorf("unexpected color format: %q", value)
+		}
+		a = uint8(math.Round(n * 255))
+	}
+	return rgb[0], rgb[1], rgb[2], a, nil
+}
+
+// WaitMinSize waits for the bounding box of the element to be at least minWidth wide and minHeight high, in CSS pixels.
+// Use it before capturing panels that animate open from zero size.
+// It returns an error including the last seen size if the element does not reach the size within opts.Timeout.
+func WaitMinSize(e *rod.Element, minWidth, minHeight float64, opts *RodOptions) error {
+	if e == nil {
+		return errors.New("rod.Element is nil")
+	}
+	if opts == nil {
+		opts = DefaultRodOptions()
+	}
+
+	// Timeout context
+	ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
+	defer cancel()
+
+	var width, height float64
+	err := pollUntil(ctx, opts.RetryDelay, func() (bool, error) {
+		res, err := e.Context(ctx).Eval(`() => { const r = this.getBoundingClientRect(); return [r.width, r.height]; }`)
+		if err != nil {
+			return false, fmt.Errorf("failed to get bounding box: %v", err)
+		}
+		size := res.Value.Arr()
+		width, height = size[0].Num(), size[1].Num()
+		return width >= minWidth && height >= minHeight, nil
+	})
+	if err != nil {
+		return fmt.Errorf("element did not reach %vx%v (last seen: %vx%v)\n%w", minWidth, minHeight, width, height, err)
+	}
+	return nil
+}
+
+// readFormJS returns the live values of the named fields of the form, keyed by name.
+const readFormJS = `() => {
+	const values = {};
+	for (const field of this.elements) {
+		if (!field.name || ['button', 'submit', 'reset', 'image', 'file'].includes(field.type)) continue;
+		if (field.type === 'checkbox') {
+			values[field.name] = String(field.checked);
+		} else if (field.type === 'radio') {
+			if (field.checked) values[field.name] = field.value;
+			else if (!(field.name in values)) values[field.name] = '';
+		} else {
+			values[field.name] = field.value;
+		}
+	}
+	return values;
+}`
+
+// ReadForm returns the current values of the named inputs, selects and textareas of the form element, keyed by field name.
+// Checkboxes are reported as "true" or "false", and a radio group as the value of its checked button, or an empty string if none is checked.
+// Buttons and file inputs are skipped, and a later field with the same name overrides an earlier one.
+// It returns the values and an error, if any.
+// If the element is not a form, it returns an error.
+func ReadForm(e *rod.Element) (map[string]string, error) {
+	if e == nil {
+		return nil, errors.New("rod.Element is nil")
+	}
+	if err := requireTag(e, "form"); err != nil {
+		return nil, err
+	}
+	res, err := e.Eval(readFormJS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read form: %v", err)
+	}
+	values := map[string]string{}
+	if err := res.Value.Unmarshal(&values); err != nil {
+		return nil, fmt.Errorf("failed to decode form values: %v", err)
+	}
+	return values, nil
+}
+
+// scrollIntoViewHJS scrolls the element into view horizontally, then adjusts every horizontally scrollable ancestor,
+// innermost first, so the element lies within its visible width.
+const scrollIntoViewHJS = `() => {
+	this.scrollIntoView({ block: 'nearest', inline: 'center' });
+	for (let node = this.parentElement; node; node = node.parentElement) {
+		const overflowX = getComputedStyle(node).overflowX;
+		if (node.scrollWidth <= node.clientWidth || !['auto', 'scroll', 'overlay'].includes(overflowX)) continue;
+		const el = this.getBoundingClientRect();
+		const box = node.getBoundingClientRect();
+		const left = box.left + node.clientLeft;
+		const right = left + node.clientWidth;
+		if (el.left < left || el.right > right) {
+			node.scrollLeft += (el.left + el.right) / 2 - (left + right) / 2;
+		}
+	}
+}`
+
+// ScrollElementIntoViewH scrolls the element horizontally into the visible width of its scroll containers, such as an off-screen column of a wide table.
+// It handles nested scroll containers by centering the element in each horizontally scrollable ancestor that clips it.
+// It returns an error if scrolling fails.
+func ScrollElementIntoViewH(e *rod.Element) error {
+	if e == nil {
+		return errors.New("rod.Element is nil")
+	}
+	if _, err := e.Eval(scrollIntoViewHJS); err != nil {
+		return fmt.Errorf("failed to scroll element into view horizontally: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+package rodutils
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// SetZoom sets the CSS zoom level of the page body, where 1 is the normal size.
+// CSS zoom changes the layout size of the content, so more of it fits into the viewport and screenshots.
+// Unlike device scale emulation, it does not change the viewport or the device pixel ratio seen by the page.
+// It returns an error if the zoom level cannot be set.
+func SetZoom(page *rod.Page, factor float64) error {
+	if page == nil {
+		return fmt.Errorf("rod.Page is nil")
+	}
+	if factor <= 0 {
+		return fmt.Errorf("zoom factor must be positive: %v", factor)
+	}
+	_, err := page.Eval(`(factor) => { document.body.style.zoom = String(factor) }`, factor)
+	if err != nil {
+		return fmt.Errorf("failed to set zoom: %v", err)
+	}
+	return nil
+}
+
+// Zoom returns the CSS zoom level of the page body, where 1 is the normal size.
+// It returns the zoom level and an error, if any.
+// If the zoom level cannot be read, it returns an error.
+func Zoom(page *rod.Page) (float64, error) {
+	if page == nil {
+		return 0, fmt.Errorf("rod.Page is nil")
+	}
+	res, err := page.Eval(`() => {
+		const zoom = document.body.style.zoom;
+		return (zoom.endsWith('%') ? parseFloat(zoom) / 100 : parseFloat(zoom)) || 1;
+	}`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get zoom: %v", err)
+	}
+	return res.Value.Num(), nil
+}
+
+// ResetEmulation clears the device metrics, touch and user agent overrides of the page.
+// It returns the page to the browser defaults, for example when reusing it across jobs with different emulation.
+// It attempts every reset and returns the combined errors of the ones that failed.
+func ResetEmulation(page *rod.Page) error {
+	if page == nil {
+		return fmt.Errorf("rod.Page is nil")
+	}
+	var errs []error
+	if err := (proto.EmulationClearDeviceMetricsOverride{}).Call(page); err != nil {
+		errs = append(errs, fmt.Errorf("failed to clear device metrics override: %w", err))
+	}
+	if err := (proto.EmulationSetTouchEmulationEnabled{Enabled: false}).Call(page); err != nil {
+		errs = append(errs, fmt.Errorf("failed to disable touch emulation: %w", err))
+	}
+	// An empty user agent removes the override
+	if err := (proto.NetworkSetUserAgentOverride{UserAgent: ""}).Call(page); err != nil {
+		errs = append(errs, fmt.Errorf("failed to clear user agent override: %w", err))
+	}
+	return combineErrors(errs)
+}
+
+// EmulateNetwork throttles the network of the page to the given bandwidth and latency.
+// The bandwidth is given in kilobits per second, and a non-positive value disables throttling in that direction.
+// It returns an error if the network conditions cannot be applied.
+func EmulateNetwork(page *rod.Page, downKbps, upKbps int, latencyMs int) error {
+	if page == nil {
+		return fmt.Errorf("rod.Page is nil")
+	}
+	throughput := func(kbps int) float64 {
+		if kbps <= 0 {
+			return -1
+		}
+		return float64(kbps) * 1000 / 8
+	}
+	return emulateNetworkConditions(page, proto.NetworkEmulateNetworkConditions{
+		Latency:            float64(latencyMs),
+		DownloadThroughput: throughput(downKbps),
+		UploadThroughput:   throughput(upKbps),
+	})
+}
+
+// EmulateCPU throttles the CPU of the page by the given slowdown factor, where 1 means no throttling.
+// It returns an error if the throttling rate cannot be applied.
+func EmulateCPU(page *rod.Page, rate float64) error {
+	if page == nil {
+		return fmt.Errorf("rod.Page is nil")
+	}
+	if rate < 1 {
+		return fmt.Errorf("CPU throttling rate must be at least 1: %v", rate)
+	}
+	err := proto.EmulationSetCPUThrottlingRate{Rate: rate}.Call(page)
+	if err != nil {
+		return fmt.Errorf("failed to set CPU throttling rate: %v", err)
+	}
+	return nil
+}
+
+// ResetThrottling removes the network and CPU throttling of the page.
+// It attempts both resets and returns the combined errors of the ones that failed.
+func ResetThrottling(page *rod.Page) error {
+	if page == nil {
+		return fmt.Errorf("rod.Page is nil")
+	}
+	var errs []error
+	err := emulateNetworkConditions(page, proto.NetworkEmulateNetworkConditions{DownloadThroughput: -1, UploadThroughput: -1})
+	if err != nil {
+		errs = append(errs, err)
+	}
+	if err := EmulateCPU(page, 1); err != nil {
+		errs = append(errs, err)
+	}
+	return combineErrors(errs)
+}
+
+// emulateNetworkConditions enables the network domain and applies the network conditions to the page.
+func emulateNetworkConditions(page *rod.Page, conditions proto.NetworkEmulateNetworkConditions) error {
+	if err := (proto.NetworkEnable{}).Call(page); err != nil {
+		return fmt.Errorf("failed to enable network domain: %v", err)
+	}
+	if err := conditions.Call(page); err != nil {
+		return fmt.Errorf("failed to emulate network conditions: %v", err)
+	}
+	return nil
+}
+
+// SetOffline emulates a lost network connection for the page, or restores it if offline is false.
+// While offline, every request of the page fails, including navigations, so call it again with false before navigating normally.
+// It returns an error if the network conditions cannot be applied.
+func SetOffline(page *rod.Page, offline bool) error {
+	if page == nil {
+		return fmt.Errorf("rod.Page is nil")
+	}
+	return emulateNetworkConditions(page, proto.NetworkEmulateNetworkConditions{
+		Offline:            offline,
+		DownloadThroughput: -1,
+		UploadThroughput:   -1,
+	})
+}
+
+// GrantPermissions grants the given permissions, such as "geolocation", "notifications" or "clipboardReadWrite",
+// to the origin of the page's current URL, so the browser never prompts for them.
+// If the page has no web origin yet, such as about:blank, the permissions are granted to all origins.
+// It returns an error if the permissions cannot be granted.
+func GrantPermissions(page *rod.Page, permissions ...string) error {
+	if page == nil {
+		return fmt.Errorf("rod.Page is nil")
+	}
+	info, err := page.Info()
+	if err != nil {
+		return fmt.Errorf("failed to get page info: %v", err)
+	}
+	var origin string
+	if u, err := url.Parse(info.URL); err == nil && (u.Scheme == "http" || u.Scheme == "https") {
+		origin = u.Scheme + "://" + u.Host
+	}
+
+	types := make([]proto.BrowserPermissionType, 0, len(permissions))
+	for _, p := range permissions {
+		types = append(types, proto.BrowserPermissionType(p))
+	}
+	browser := page.Browser()
+	err = proto.BrowserGrantPermissions{
+		Permissions:      types,
+		Origin:           origin,
+		BrowserContextID: browser.BrowserContextID,
+	}.Call(browser)
+	if err != nil {
+		return fmt.Errorf("failed to grant permissions: %v\n%v", permissions, err)
+	}
+	return nil
+}
+
+// SetGeolocation overrides the geolocation reported to the page, with the accuracy given in meters.
+// Combine it with GrantPermissions(page, "geolocation") so the page can read the position without a prompt.
+// It returns an error if the override cannot be applied.
+func SetGeolocation(page *rod.Page, lat, lng, accuracy float64) error {
+	if page == nil {
+		return fmt.Errorf("rod.Page is nil")
+	}
+	err := proto.EmulationSetGeolocationOverride{Latitude: &lat, Longitude: &lng, Accuracy: &accuracy}.Call(page)
+	if err != nil {
+		return fmt.Errorf("failed to set geolocation: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package rodutils
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// MultiError aggregates the errors returned by batch operations.
+// It implements Unwrap() []error so errors.Is and errors.As inspect every aggregated error.
+type MultiError struct {
+	Errors []error
+}
+
+// Error returns the aggregated error messages, one per line.
+func (m *MultiError) Error() string {
+	msgs := make([]string, 0, len(m.Errors))
+	for _, err := range m.Errors {
+		msgs = append(msgs, "* "+err.Error())
+	}
+	return fmt.Sprintf("%d errors occurred:\n%s", len(m.Errors), strings.Join(msgs, "\n"))
+}
+
+// Unwrap returns the aggregated errors.
+func (m *MultiError) Unwrap() []error {
+	return m.Errors
+}
+
+// combineErrors combines the given errors into a single error, ignoring nil entries.
+// It returns nil if there are no errors and the error itself if there is only one.
+// Otherwise, it returns a MultiError.
+func combineErrors(errs []error) error {
+	var nonNil []error
+	for _, err := range errs {
+		if err != nil {
+			nonNil = append(nonNil, err)
+		}
+	}
+	switch len(nonNil) {
+	case 0:
+		return nil
+	case 1:
+		return nonNil[0]
+	default:
+		return &MultiError{Errors: nonNil}
+	}
+}
+
+// ErrElementObscured is returned when another element covers the element to be clicked.
+var ErrElementObscured = errors.New("element is obscured by another element")
+
+// ErrCircuitOpen is returned by CircuitBreaker.Do while the circuit is open.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
@@ -0,0 +1,124 @@
+package rodutils
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// subscribe starts handling the given page events in the background.
+// The callbacks follow the signature accepted by rod.Page.EachEvent.
+// It returns a function that stops the handling and waits for the in-flight callback to finish.
+func subscribe(page *rod.Page, callbacks ...interface{}) (stop func()) {
+	ctx, cancel := context.WithCancel(page.GetContext())
+	wait := page.Context(ctx).EachEvent(callbacks...)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		wait()
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			cancel()
+			<-done
+		})
+	}
+}
+
+// CaptureConsole records the console messages of the page.
+// Each message is formatted as "[level] text", with the arguments joined by spaces.
+// It returns a function that returns a copy of the messages recorded so far, a function to stop recording, and an error, if any.
+// The messages are appended from a background goroutine, and the copy is taken under the same lock,
+// so it is safe to read them while recording as well as after calling stop.
+func CaptureConsole(page *rod.Page) (messages func() []string, stop func(), err error) {
+	if page == nil {
+		return nil, nil, fmt.Errorf("rod.Page is nil")
+	}
+	var mu sync.Mutex
+	logs := []string{}
+
+	stop = subscribe(page, func(e *proto.RuntimeConsoleAPICalled) {
+		args := make([]string, 0, len(e.Args))
+		for _, arg := range e.Args {
+			args = append(args, formatRemoteObject(arg))
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		logs = append(logs, fmt.Sprintf("[%s] %s", e.Type, strings.Join(args, " ")))
+	})
+
+	messages = func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return slices.Clone(logs)
+	}
+	return messages, stop, nil
+}
+
+// formatRemoteObject returns a human-readable representation of the remote object.
+func formatRemoteObject(obj *proto.RuntimeRemoteObject) string {
+	switch {
+	case obj.Type == proto.RuntimeRemoteObjectTypeString:
+		return obj.Value.Str()
+	case obj.UnserializableValue != "":
+		return string(obj.UnserializableValue)
+	case obj.Description != "":
+		return obj.Description
+	default:
+		return obj.Value.String()
+	}
+}
+
+// HandleBeforeUnload automatically answers the beforeunload dialogs of the page, such as the prompt shown when leaving an edited form.
+// If accept is true the page is left, otherwise the navigation is cancelled and the page stays.
+// It must be armed before the action that triggers the navigation, since an unanswered dialog blocks the page.
+// Other JavaScript dialogs are left untouched.
+// It returns a function to stop handling and an error, if any.
+func HandleBeforeUnload(page *rod.Page, accept bool) (stop func(), err error) {
+	if page == nil {
+		return nil, fmt.Errorf("rod.Page is nil")
+	}
+	stop = subscribe(page, func(e *proto.PageJavascriptDialogOpening) {
+		if e.Type != proto.PageDialogTypeBeforeunload {
+			return
+		}
+		_ = proto.PageHandleJavaScriptDialog{Accept: accept}.Call(page)
+	})
+	return stop, nil
+}
+
+// AssertNoPageErrors runs the flow and records the uncaught JavaScript exceptions thrown by the page meanwhile.
+// It returns the exception messages, and an error combining the error of the flow with an error reporting the exceptions, if any.
+func AssertNoPageErrors(page *rod.Page, flow func() error) ([]string, error) {
+	if page == nil {
+		return nil, fmt.Errorf("rod.Page is nil")
+	}
+	var mu sync.Mutex
+	messages := []string{}
+
+	stop := subscribe(page, func(e *proto.RuntimeExceptionThrown) {
+		msg := e.ExceptionDetails.Text
+		if e.ExceptionDetails.Exception != nil && e.ExceptionDetails.Exception.Description != "" {
+			msg = e.ExceptionDetails.Exception.Description
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		messages = append(messages, msg)
+	})
+	flowErr := flow()
+	stop()
+
+	var pageErr error
+	if len(messages) > 0 {
+		pageErr = fmt.Errorf("%d JavaScript errors occurred:\n%s", len(messages), strings.Join(messages, "\n"))
+	}
+	return messages, combineErrors([]error{flowErr, pageErr})
+}
@@ -0,0 +1,142 @@
+package rodutils
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/go-rod/rod"
+)
+
+// pageLinksJS collects the absolute URLs of the anchors in document order, without duplicates.
+const pageLinksJS = `(sameOrigin) => {
+	const seen = new Set();
+	const links = [];
+	for (const a of document.querySelectorAll('a[href]')) {
+		let url;
+		try { url = new URL(a.getAttribute('href'), document.baseURI); } catch (e) { continue; }
+		if (sameOrigin && url.origin !== location.origin) continue;
+		if (seen.has(url.href)) continue;
+		seen.add(url.href);
+		links.push(url.href);
+	}
+	return links;
+}`
+
+// PageLinks returns the URLs of all anchors with an href in the page.
+// Relative hrefs are resolved against the document base URL, and duplicates are removed keeping document order.
+// It returns the URLs and an error, if any.
+func PageLinks(page *rod.Page) ([]string, error) {
+	return pageLinks(page, false)
+}
+
+// SameOriginLinks is like PageLinks, but only returns the URLs with the same origin as the page.
+func SameOriginLinks(page *rod.Page) ([]string, error) {
+	return pageLinks(page, true)
+}
+
+// pageLinks returns the absolute URLs of the anchors in the page, optionally limited to the page's origin.
+func pageLinks(page *rod.Page, sameOrigin bool) ([]string, error) {
+	if page == nil {
+		return nil, fmt.Errorf("rod.Page is nil")
+	}
+	res, err := page.Eval(pageLinksJS, sameOrigin)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get links: %v", err)
+	}
+	links := []string{}
+	if err := res.Value.Unmarshal(&links); err != nil {
+		return nil, fmt.Errorf("failed to decode links: %v", err)
+	}
+	return links, nil
+}
+
+// MetaTags returns the content of the meta tags in the page keyed by their name or property attribute, including OpenGraph og:* tags.
+// Tags without a name or property are skipped, and a later tag with the same key overrides an earlier one.
+// It returns the tags and an error, if any.
+func MetaTags(page *rod.Page) (map[string]string, error) {
+	if page == nil {
+		return nil, fmt.Errorf("rod.Page is nil")
+	}
+	res, err := page.Eval(`() => {
+		const tags = {};
+		for (const meta of document.querySelectorAll('meta')) {
+			const key = meta.getAttribute('name') || meta.getAttribute('property');
+			if (!key) continue;
+			tags[key] = meta.getAttribute('content') || '';
+		}
+		return tags;
+	}`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get meta tags: %v", err)
+	}
+	tags := map[string]string{}
+	if err := res.Value.Unmarshal(&tags); err != nil {
+		return nil, fmt.Errorf("failed to decode meta tags: %v", err)
+	}
+	return tags, nil
+}
+
+// ExtractJSONLD returns the contents of the JSON-LD scripts in the page as raw JSON messages for the caller to unmarshal.
+// Scripts that do not contain valid JSON are skipped.
+// It returns the messages and an error, if any.
+func ExtractJSONLD(page *rod.Page) ([]json.RawMessage, error) {
+	messages, _, err := ExtractJSONLDReport(page)
+	return messages, err
+}
+
+// ExtractJSONLDReport is like ExtractJSONLD, but also returns the number of malformed scripts that were skipped.
+func ExtractJSONLDReport(page *rod.Page) ([]json.RawMessage, int, error) {
+	if page == nil {
+		return nil, 0, fmt.Errorf("rod.Page is nil")
+	}
+	res, err := page.Eval(`() => Array.from(document.querySelectorAll('script[type="application/ld+json"]'), s => s.textContent)`)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get JSON-LD scripts: %v", err)
+	}
+
+	messages := []json.RawMessage{}
+	skipped := 0
+	for _, script := range res.Value.Arr() {
+		data := []byte(strings.TrimSpace(script.Str()))
+		if !json.Valid(data) {
+			skipped++
+			continue
+		}
+		messages = append(messages, json.RawMessage(data))
+	}
+	return messages, skipped, nil
+}
+
+// ImageInfo describes an image in the page.
+type ImageInfo struct {
+	Src           string `json:"src"`           // Absolute URL of the displayed image
+	Alt           string `json:"alt"`           // Alternative text, empty if missing
+	NaturalWidth  int    `json:"naturalWidth"`  // Intrinsic width in pixels, 0 until loaded
+	NaturalHeight int    `json:"naturalHeight"` // Intrinsic height in pixels, 0 until loaded
+}
+
+// PageImages returns the images in the page in document order, collected in a single evaluation.
+// Relative sources are resolved against the document base URL, and images without a source are skipped.
+// It returns the images and an error, if any.
+func PageImages(page *rod.Page) ([]ImageInfo, error) {
+	if page == nil {
+		return nil, fmt.Errorf("rod.Page is nil")
+	}
+	res, err := page.Eval(`() => Array.from(document.images)
+		.filter(img => img.currentSrc || img.getAttribute('src'))
+		.map(img => ({
+			src: img.currentSrc || img.src,
+			alt: img.getAttribute('alt') || '',
+			naturalWidth: img.naturalWidth,
+			naturalHeight: img.naturalHeight,
+		}))`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get images: %v", err)
+	}
+	images := []ImageInfo{}
+	if err := res.Value.Unmarshal(&images); err != nil {
+		return nil, fmt.Errorf("failed to decode images: %v", err)
+	}
+	return images, nil
+}
@@ -0,0 +1,166 @@
+package rodutils
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-rod/rod"
+)
+
+// frameTagSelector matches the elements that embed a frame.
+const frameTagSelector = "iframe, frame"
+
+// ElementsAllFrames finds all elements matching the selector in the page and in every reachable frame, including nested ones.
+// It returns the matches in document order of the main document followed by each frame, and an error, if any.
+// Frames that cannot be accessed, such as cross-origin frames, are skipped.
+// If no elements are found, it returns an error.
+func ElementsAllFrames(page *rod.Page, selector string) (rod.Elements, error) {
+	elems, _, err := ElementsAllFramesReport(page, selector)
+	return elems, err
+}
+
+// ElementsAllFramesReport is like ElementsAllFrames, but also returns the number of frames that were skipped
+// because they could not be accessed.
+func ElementsAllFramesReport(page *rod.Page, selector string) (rod.Elements, int, error) {
+	if page == nil {
+		return nil, 0, fmt.Errorf("rod.Page is nil")
+	}
+	elems, skipped, err := frameElements(page, selector)
+	if err != nil {
+		return nil, skipped, err
+	}
+	if len(elems) == 0 {
+		return nil, skipped, fmt.Errorf("the number of acquired elements was 0: %s", selector)
+	}
+	return elems, skipped, nil
+}
+
+// frameElements collects the elements matching the selector in the document of p and its descendant frames.
+// It returns the elements, the number of skipped frames, and an error if the document of p cannot be queried.
+func frameElements(p *rod.Page, selector string) (rod.Elements, int, error) {
+	elems, err := p.Elements(selector)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get elements: %s\n%v", selector, err)
+	}
+	frames, err := p.Elements(frameTagSelector)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get frames: %v", err)
+	}
+
+	skipped := 0
+	for _, f := range frames {
+		if accessible, err := frameAccessible(f); err != nil || !accessible {
+			skipped++
+			continue
+		}
+		frame, err := f.Frame()
+		if err != nil {
+			skipped++
+			continue
+		}
+		nested, nestedSkipped, err := frameElements(frame, selector)
+		if err != nil {
+			skipped++
+			continue
+		}
+		elems = append(elems, nested...)
+		skipped += nestedSkipped
+	}
+	return elems, skipped, nil
+}
+
+// frameAccessible reports whether the document of the frame element can be accessed from the embedding page.
+// Cross-origin frames report false, since their contentDocument is hidden.
+func frameAccessible(f *rod.Element) (bool, error) {
+	res, err := f.Eval(`() => { try { return this.contentDocument !== null; } catch (e) { return false; } }`)
+	if err != nil {
+		return false, fmt.Errorf("failed to check frame access: %v", err)
+	}
+	return res.Value.Bool(), nil
+}
+
+// FrameHTML waits for the frame matching frameSelector to load and returns the HTML of its document.
+// It returns the HTML and an error, if any.
+// If the frame is not found, is cross-origin, or does not load within opts.Timeout, it returns an error.
+func FrameHTML(page *rod.Page, frameSelector string, opts *RodOptions) (string, error) {
+	if page == nil {
+		return "", fmt.Errorf("rod.Page is nil")
+	}
+	if opts == nil {
+		opts = DefaultRodOptions()
+	}
+
+	// Timeout context
+	ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
+	defer cancel()
+
+	frame, err := loadedFrame(page.Context(ctx), frameSelector, opts)
+	if err != nil {
+		return "", err
+	}
+	html, err := frame.HTML()
+	if err != nil {
+		return "", fmt.Errorf("failed to get frame HTML: %s\n%w", frameSelector, err)
+	}
+	return html, nil
+}
+
+// IntoFrame waits for the frame matching frameSelector to load and returns its page, so every page helper, such as PageElement or SafeClick, works inside the frame.
+// It returns the frame page and an error, if any.
+// If the frame is not found, is cross-origin, or does not load within opts.Timeout, it returns an error.
+func IntoFrame(page *rod.Page, frameSelector string, opts *RodOptions) (*rod.Page, error) {
+	if page == nil {
+		return nil, fmt.Errorf("rod.Page is nil")
+	}
+	if opts == nil {
+		opts = DefaultRodOptions()
+	}
+
+	// Timeout context
+	ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
+	defer cancel()
+
+	frame, err := loadedFrame(page.Context(ctx), frameSelector, opts)
+	if err != nil {
+		return nil, err
+	}
+	// Detach the frame from the timeout context so it stays usable
+	return frame.Context(page.GetContext()), nil
+}
+
+// loadedFrame waits for the frame matching frameSelector in p and returns its page once the frame document has loaded.
+// The waits are bound to the context of p.
+func loadedFrame(p *rod.Page, frameSelector string, opts *RodOptions) (*rod.Page, error) {
+	if err := opts.validateSelector(frameSelector); err != nil {
+		return nil, err
+	}
+	ctx := p.GetContext()
+	var elem *rod.Element
+	err := pollUntil(ctx, opts.RetryDelay, func() (bool, error) {
+		has, el, err := p.Has(frameSelector)
+		if err != nil {
+			return false, err
+		}
+		elem = el
+		return has, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("frame not found: %s\n%w", frameSelector, err)
+	}
+
+	accessible, err := frameAccessible(elem)
+	if err != nil {
+		return nil, err
+	}
+	if !accessible {
+		return nil, fmt.Errorf("frame is cross-origin and cannot be accessed: %s", frameSelector)
+	}
+	frame, err := elem.Frame()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get frame: %s\n%w", frameSelector, err)
+	}
+	if err := frame.WaitLoad(); err != nil {
+		return nil, fmt.Errorf("failed to wait for frame to load: %s\n%w", frameSelector, err)
+	}
+	return frame, nil
+}
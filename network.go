@@ -0,0 +1,520 @@
+package rodutils
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// RequestRecord describes a network request made by the page.
+type RequestRecord struct {
+	URL    string                    // Request URL
+	Method string                    // HTTP method
+	Status int                       // HTTP status code, 0 until a response is received
+	Type   proto.NetworkResourceType // Resource type, such as Document, XHR or Image
+}
+
+// RecordRequests records every network request made by the page, including redirect hops.
+// It returns a function that returns a copy of the requests recorded so far, a function to stop recording, and an error, if any.
+// The records are updated from a background goroutine, and the copy is taken under the same lock,
+// so it is safe to read them while recording as well as after calling stop.
+// Every request is kept in memory until the records are released, so stop recording promptly on request-heavy pages.
+func RecordRequests(page *rod.Page) (requests func() []RequestRecord, stop func(), err error) {
+	if page == nil {
+		return nil, nil, fmt.Errorf("rod.Page is nil")
+	}
+	var mu sync.Mutex
+	records := []RequestRecord{}
+	index := map[proto.NetworkRequestID]int{}
+
+	stop = subscribe(page,
+		func(e *proto.NetworkRequestWillBeSent) {
+			mu.Lock()
+			defer mu.Unlock()
+			// A redirect reuses the request id, so complete the previous hop first
+			if i, ok := index[e.RequestID]; ok && e.RedirectResponse != nil {
+				records[i].Status = e.RedirectResponse.Status
+			}
+			index[e.RequestID] = len(records)
+			records = append(records, RequestRecord{
+				URL:    e.Request.URL,
+				Method: e.Request.Method,
+				Type:   e.Type,
+			})
+		},
+		func(e *proto.NetworkResponseReceived) {
+			mu.Lock()
+			defer mu.Unlock()
+			if i, ok := index[e.RequestID]; ok {
+				records[i].Status = e.Response.Status
+				records[i].Type = e.Type
+			}
+		},
+	)
+
+	requests = func() []RequestRecord {
+		mu.Lock()
+		defer mu.Unlock()
+		return slices.Clone(records)
+	}
+	return requests, stop, nil
+}
+
+// WaitRequestsBelow waits until the number of in-flight requests of the page stays at or below max for sustain.
+// Unlike a strict network idle wait, it tolerates long-polling and keep-alive connections that never finish.
+// Only requests started after the call are tracked.
+// It returns an error if the traffic does not calm down within opts.Timeout.
+func WaitRequestsBelow(page *rod.Page, max int, sustain time.Duration, opts *RodOptions) error {
+	if page == nil {
+		return fmt.Errorf("rod.Page is nil")
+	}
+	if opts == nil {
+		opts = DefaultRodOptions()
+	}
+
+	var mu sync.Mutex
+	inflight := map[proto.NetworkRequestID]struct{}{}
+	done := func(id proto.NetworkRequestID) {
+		mu.Lock()
+		defer mu.Unlock()
+		delete(inflight, id)
+	}
+	stop := subscribe(page,
+		func(e *proto.NetworkRequestWillBeSent) {
+			mu.Lock()
+			defer mu.Unlock()
+			inflight[e.RequestID] = struct{}{}
+		},
+		func(e *proto.NetworkLoadingFinished) { done(e.RequestID) },
+		func(e *proto.NetworkLoadingFailed) { done(e.RequestID) },
+	)
+	defer stop()
+
+	// Timeout context
+	ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
+	defer cancel()
+
+	var count int
+	var calmSince time.Time
+	err := pollUntil(ctx, min(opts.RetryDelay, sustain), func() (bool, error) {
+		mu.Lock()
+		count = len(inflight)
+		mu.Unlock()
+		if count > max {
+			calmSince = time.Time{}
+			return false, nil
+		}
+		if calmSince.IsZero() {
+			calmSince = time.Now()
+		}
+		return time.Since(calmSince) >= sustain, nil
+	})
+	if err != nil {
+		return fmt.Errorf("in-flight requests did not drop to %d (last seen: %d)\n%w", max, count, err)
+	}
+	return nil
+}
+
+// WaitResponses waits for count responses whose URL matches the urlPattern glob, such as "*/api/items*", and returns their bodies.
+// The responses are observed passively through network events, so the requests keep the browser's cookies, credentials and proxy settings,
+// and only requests started after the call are seen.
+// The bodies are ordered by the time their responses finished loading, which may differ from the request order.
+// It returns an error if fewer than count responses arrive within opts.Timeout.
+func WaitResponses(page *rod.Page, urlPattern string, count int, opts *RodOptions) ([][]byte, error) {
+	if page == nil {
+		return nil, fmt.Errorf("rod.Page is nil")
+	}
+	if opts == nil {
+		opts = DefaultRodOptions()
+	}
+	if count <= 0 {
+		return [][]byte{}, nil
+	}
+	pattern, err := regexp.Compile(proto.PatternToReg(urlPattern))
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL pattern: %s\n%v", urlPattern, err)
+	}
+
+	var mu sync.Mutex
+	matched := map[proto.NetworkRequestID]struct{}{}
+	bodies := [][]byte{}
+	var bodyErr error
+	done := make(chan struct{})
+
+	stop := subscribe(page,
+		func(e *proto.NetworkResponseReceived) {
+			if !pattern.MatchString(e.Response.URL) {
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			matched[e.RequestID] = struct{}{}
+		},
+		func(e *proto.NetworkLoadingFinished) {
+			mu.Lock()
+			_, ok := matched[e.RequestID]
+			delete(matched, e.RequestID)
+			mu.Unlock()
+			if !ok {
+				return
+			}
+
+			body, err := responseBody(page, e.RequestID)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				bodyErr = err
+				return
+			}
+			if len(bodies) < count {
+				bodies = append(bodies, body)
+				if len(bodies) == count {
+					close(done)
+				}
+			}
+		},
+	)
+	defer stop()
+
+	select {
+	case <-done:
+		mu.Lock()
+		defer mu.Unlock()
+		return bodies, nil
+	case <-time.After(opts.Timeout):
+		mu.Lock()
+		defer mu.Unlock()
+		if bodyErr != nil {
+			return nil, fmt.Errorf("received %d of %d responses: %s\n%w", len(bodies), count, urlPattern, bodyErr)
+		}
+		return nil, fmt.Errorf("received %d of %d responses: %s\noperation timed out", len(bodies), count, urlPattern)
+	}
+}
+
+// responseBody returns the body of the finished response of the request, decoding base64 encoded bodies.
+func responseBody(page *rod.Page, id proto.NetworkRequestID) ([]byte, error) {
+	res, err := proto.NetworkGetResponseBody{RequestID: id}.Call(page)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get response body: %w", err)
+	}
+	if !res.Base64Encoded {
+		return []byte(res.Body), nil
+	}
+	body, err := base64.StdEncoding.DecodeString(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode response body: %w", err)
+	}
+	return body, nil
+}
+
+// FailedRequest describes a request that failed with an HTTP error status or a network error.
+type FailedRequest struct {
+	URL       string // Request URL
+	Status    int    // HTTP status code, 0 for network errors
+	ErrorText string // Network error text, empty for HTTP error statuses
+}
+
+// RecordFailures records the requests of the page that respond with a status of 400 or above, or fail at the network level.
+// It returns a function that returns a copy of the failures recorded so far, a function to stop recording, and an error, if any.
+// The listener stays attached until stop is called, and the failures are appended from a background goroutine.
+// The copy is taken under the same lock, so it is safe to read them while recording as well as after calling stop.
+// The URL of every request is kept in memory while recording, to report requests that fail before a response arrives.
+func RecordFailures(page *rod.Page) (failed func() []FailedRequest, stop func(), err error) {
+	if page == nil {
+		return nil, nil, fmt.Errorf("rod.Page is nil")
+	}
+	var mu sync.Mutex
+	failures := []FailedRequest{}
+	urls := map[proto.NetworkRequestID]string{}
+
+	stop = subscribe(page,
+		func(e *proto.NetworkRequestWillBeSent) {
+			mu.Lock()
+			defer mu.Unlock()
+			urls[e.RequestID] = e.Request.URL
+		},
+		func(e *proto.NetworkResponseReceived) {
+			if e.Response.Status < 400 {
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			failures = append(failures, FailedRequest{URL: e.Response.URL, Status: e.Response.Status})
+		},
+		func(e *proto.NetworkLoadingFailed) {
+			mu.Lock()
+			defer mu.Unlock()
+			failures = append(failures, FailedRequest{URL: urls[e.RequestID], ErrorText: e.ErrorText})
+		},
+	)
+
+	failed = func() []FailedRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		return slices.Clone(failures)
+	}
+	return failed, stop, nil
+}
+
+// ResolveRedirects navigates the page to url, follows the redirects, and waits for the landing page to load.
+// The hops are the document URLs requested by the main frame before the final one, starting with url itself,
+// and cover both HTTP redirects and client-side redirects that happen before the load event.
+// It returns the final URL, the hops, and an error, if any.
+// If the navigation or the load does not complete within opts.Timeout, it returns an error.
+func ResolveRedirects(page *rod.Page, url string, opts *RodOptions) (finalURL string, hops []string, err error) {
+	if page == nil {
+		return "", nil, fmt.Errorf("rod.Page is nil")
+	}
+	if opts == nil {
+		opts = DefaultRodOptions()
+	}
+
+	// Timeout context
+	ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
+	defer cancel()
+	p := page.Context(ctx)
+
+	var mu sync.Mutex
+	chain := []string{}
+	stop := subscribe(p, func(e *proto.NetworkRequestWillBeSent) {
+		if e.Type != proto.NetworkResourceTypeDocument || e.FrameID != page.FrameID {
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		chain = append(chain, e.Request.URL)
+	})
+	defer stop()
+
+	if err := p.Navigate(url); err != nil {
+		return "", nil, fmt.Errorf("failed to navigate to page: %s\n%w", url, err)
+	}
+	if err := p.WaitLoad(); err != nil {
+		return "", nil, fmt.Errorf("failed to wait for page to load: %s\n%w", url, err)
+	}
+	info, err := p.Info()
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get page info: %w", err)
+	}
+	stop()
+
+	finalURL = info.URL
+	hops = chain
+	if n := len(hops); n > 0 && hops[n-1] == finalURL {
+		hops = hops[:n-1]
+	}
+	return finalURL, hops, nil
+}
+
+// postedForm holds the body and content type of a captured POST request.
+type postedForm struct {
+	body        string
+	contentType string
+}
+
+// CaptureFormPost runs the trigger and returns the body of the first POST request whose URL matches the urlPattern glob.
+// Form-encoded bodies keep the first value of each field, and JSON object bodies keep string values as is and encode the other values as JSON.
+// The request is observed through the network events without being intercepted, so it reaches the server unchanged
+// and any router the caller runs with rod.Page.HijackRequests keeps working.
+// It returns an error if the trigger fails, the body cannot be read or parsed, or no matching request is sent within opts.Timeout.
+func CaptureFormPost(page *rod.Page, urlPattern string, trigger func() error, opts *RodOptions) (map[string]string, error) {
+	if page == nil {
+		return nil, fmt.Errorf("rod.Page is nil")
+	}
+	if opts == nil {
+		opts = DefaultRodOptions()
+	}
+	pattern, err := regexp.Compile(proto.PatternToReg(urlPattern))
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL pattern: %s\n%v", urlPattern, err)
+	}
+
+	posted := make(chan postedForm, 1)
+	failed := make(chan error, 1)
+	stop := subscribe(page, func(e *proto.NetworkRequestWillBeSent) {
+		if e.Request.Method != http.MethodPost || !pattern.MatchString(e.Request.URL) {
+			return
+		}
+		body, err := requestPostData(page, e)
+		if err != nil {
+			select {
+			case failed <- err:
+			default:
+			}
+			return
+		}
+		select {
+		case posted <- postedForm{body: body, contentType: requestHeader(e.Request.Headers, "Content-Type")}:
+		default:
+		}
+	})
+	defer stop()
+
+	if err := trigger(); err != nil {
+		return nil, fmt.Errorf("failed to trigger form submission: %w", err)
+	}
+
+	select {
+	case form := <-posted:
+		return parseFormBody(form)
+	case err := <-failed:
+		return nil, fmt.Errorf("failed to capture form submission: %s\n%w", urlPattern, err)
+	case <-time.After(opts.Timeout):
+		return nil, fmt.Errorf("no form submission captured: %s\noperation timed out", urlPattern)
+	}
+}
+
+// requestPostData returns the body of the request.
+// Bodies too long to be included in the event are fetched from the browser.
+func requestPostData(page *rod.Page, e *proto.NetworkRequestWillBeSent) (string, error) {
+	if e.Request.PostData != "" || !e.Request.HasPostData {
+		return e.Request.PostData, nil
+	}
+	res, err := proto.NetworkGetRequestPostData{RequestID: e.RequestID}.Call(page)
+	if err != nil {
+		return "", fmt.Errorf("failed to get request body: %w", err)
+	}
+	return res.PostData, nil
+}
+
+// requestHeader returns the value of the header, matching its name case-insensitively.
+// It returns an empty string if the header is not set.
+func requestHeader(headers proto.NetworkHeaders, name string) string {
+	for key, value := range headers {
+		if strings.EqualFold(key, name) {
+			return value.Str()
+		}
+	}
+	return ""
+}
+
+// parseFormBody parses a form-encoded or JSON object body into a map of field values.
+// It returns an error for other content types.
+func parseFormBody(form postedForm) (map[string]string, error) {
+	mediaType, _, _ := mime.ParseMediaType(form.contentType)
+	values := map[string]string{}
+	switch {
+	case mediaType == "application/x-www-form-urlencoded":
+		query, err := url.ParseQuery(form.body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse form body: %v", err)
+		}
+		for key, vals := range query {
+			values[key] = vals[0]
+		}
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		fields := map[string]json.RawMessage{}
+		if err := json.Unmarshal([]byte(form.body), &fields); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON body: %v", err)
+		}
+		for key, raw := range fields {
+			var s string
+			if err := json.Unmarshal(raw, &s); err == nil {
+				values[key] = s
+				continue
+			}
+			values[key] = string(raw)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported form content type: %q", form.contentType)
+	}
+	return values, nil
+}
+
+// WaitWebSocketFrame waits for a WebSocket frame received by the page whose payload satisfies the predicate, and returns the payload.
+// Only frames received after the call are seen, and binary frames are passed to the predicate base64 encoded.
+// The listener is removed before returning.
+// It returns an error if no matching frame arrives within opts.Timeout.
+func WaitWebSocketFrame(page *rod.Page, predicate func(payload string) bool, opts *RodOptions) (string, error) {
+	if page == nil {
+		return "", fmt.Errorf("rod.Page is nil")
+	}
+	if opts == nil {
+		opts = DefaultRodOptions()
+	}
+
+	matched := make(chan string, 1)
+	stop := subscribe(page, func(e *proto.NetworkWebSocketFrameReceived) {
+		if e.Response == nil || !predicate(e.Response.PayloadData) {
+			return
+		}
+		select {
+		case matched <- e.Response.PayloadData:
+		default:
+		}
+	})
+	defer stop()
+
+	select {
+	case payload := <-matched:
+		return payload, nil
+	case <-time.After(opts.Timeout):
+		return "", fmt.Errorf("no matching WebSocket frame received\noperation timed out")
+	}
+}
+
+// WaitResourcesLoaded waits for count responses of the resource type, such as proto.NetworkResourceTypeImage, to finish loading.
+// It is a network-level alternative to waiting for a DOM element count, and only tracks requests started after the call.
+// The listeners are removed before returning.
+// It returns an error including the number loaded so far if fewer than count finish within opts.Timeout.
+func WaitResourcesLoaded(page *rod.Page, resourceType proto.NetworkResourceType, count int, opts *RodOptions) error {
+	if page == nil {
+		return fmt.Errorf("rod.Page is nil")
+	}
+	if opts == nil {
+		opts = DefaultRodOptions()
+	}
+	if count <= 0 {
+		return nil
+	}
+
+	var mu sync.Mutex
+	pending := map[proto.NetworkRequestID]struct{}{}
+	loaded := 0
+	done := make(chan struct{})
+
+	stop := subscribe(page,
+		func(e *proto.NetworkResponseReceived) {
+			if e.Type != resourceType {
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			pending[e.RequestID] = struct{}{}
+		},
+		func(e *proto.NetworkLoadingFinished) {
+			mu.Lock()
+			defer mu.Unlock()
+			if _, ok := pending[e.RequestID]; !ok {
+				return
+			}
+			delete(pending, e.RequestID)
+			loaded++
+			if loaded == count {
+				close(done)
+			}
+		},
+	)
+	defer stop()
+
+	select {
+	case <-done:
+		return nil
+	case <-time.After(opts.Timeout):
+		mu.Lock()
+		defer mu.Unlock()
+		return fmt.Errorf("loaded %d of %d %s resources\noperation timed out", loaded, count, resourceType)
+	}
+}
@@ -2,10 +2,17 @@ package rodutils
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/input"
 	"github.com/go-rod/rod/lib/proto"
 )
 
@@ -106,14 +113,51 @@ func ScrollToBottom(page *rod.Page) error {
 	return nil
 }
 
+// wheelStepInterval is the delay between consecutive wheel events dispatched by WheelScroll.
+const wheelStepInterval = 16 * time.Millisecond
+
+// WheelScroll dispatches steps mouse wheel events, each scrolling by deltaX and deltaY, at the current mouse position.
+// Unlike setting the scroll position, it fires wheel listeners, such as infinite-scroll handlers that ignore scrollTo.
+// The total scroll distance is steps times the deltas.
+// It returns an error if steps is not positive or dispatching an event fails.
+func WheelScroll(page *rod.Page, deltaX, deltaY float64, steps int) error {
+	if page == nil {
+		return fmt.Errorf("rod.Page is nil")
+	}
+	if steps <= 0 {
+		return fmt.Errorf("wheel steps must be positive: %d", steps)
+	}
+	unlock := lockMouse(page)
+	defer unlock()
+	for i := 0; i < steps; i++ {
+		if i > 0 {
+			time.Sleep(wheelStepInterval)
+		}
+		if err := page.Mouse.Scroll(deltaX, deltaY, 0); err != nil {
+			return fmt.Errorf("failed to dispatch wheel event: %v", err)
+		}
+	}
+	return nil
+}
+
 type RodOptions struct {
-	Timeout        time.Duration // Overall timeout
-	StableDuration time.Duration // Time the element needs to be stable
-	RetryCount     int           // Number of retries
-	RetryDelay     time.Duration // Wait time between retries
-	MustVisible    bool          // Whether the element needs to be visible
-	MustStable     bool          // Whether the element needs to be stable
-	MustWaitLoad   bool          // Whether the page load needs to be complete
+	Timeout        time.Duration    // Overall timeout
+	StableDuration time.Duration    // Time the element needs to be stable
+	RetryCount     int              // Number of retries
+	RetryDelay     time.Duration    // Wait time between retries
+	MustVisible    bool             // Whether the element needs to be visible
+	MustStable     bool             // Whether the element needs to be stable
+	MustWaitLoad   bool             // Whether the page load needs to be complete
+	RetryIf        func(error) bool // Whether a failed attempt should be retried, nil retries every error
+	CheckClickable bool             // Whether SafeClick checks that no other element covers the element
+	StopOnError    bool             // Whether batch helpers stop at the first failure instead of continuing
+	StableChecks   int              // Consecutive stability checks SafeElement and SafeClick require, each adding at least StableDuration of latency
+
+	// Selectors of overlays, such as cookie banners, that SafeClick clicks to dismiss before retrying when CheckClickable finds the element obscured
+	DismissInterceptors []string
+
+	// Whether every helper taking a selector and RodOptions checks the selector syntax with ValidateSelector before using it
+	ValidateSelectors bool
 }
 
 // DefaultRodOptions returns the default options.
@@ -126,7 +170,40 @@ func DefaultRodOptions() *RodOptions {
 		MustVisible:    true,
 		MustWaitLoad:   true,
 		MustStable:     true,
+		StableChecks:   1,
+	}
+}
+
+// shouldRetry reports whether the failed attempt that returned err should be retried.
+func (o *RodOptions) shouldRetry(err error) bool {
+	return o.RetryIf == nil || o.RetryIf(err)
+}
+
+// validateSelector checks the syntax of the selectors with ValidateSelector if ValidateSelectors is set.
+func (o *RodOptions) validateSelector(selectors ...string) error {
+	if !o.ValidateSelectors {
+		return nil
+	}
+	for _, selector := range selectors {
+		if err := ValidateSelector(selector); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// waitStable waits for the element to pass StableChecks consecutive stability checks, separated by a short gap.
+// A StableChecks value below 1 is treated as a single check.
+func (o *RodOptions) waitStable(el *rod.Element) error {
+	for i := 0; i < max(o.StableChecks, 1); i++ {
+		if i > 0 {
+			time.Sleep(DefaultPollInterval)
+		}
+		if err := el.WaitStable(o.StableDuration); err != nil {
+			return err
+		}
 	}
+	return nil
 }
 
 // SafeClick executes a click after waiting for the element to stabilize.
@@ -135,50 +212,85 @@ func SafeClick(page *rod.Page, selector string, opts *RodOptions) error {
 	if opts == nil {
 		opts = DefaultRodOptions()
 	}
-	var lastErr error
+	if err := opts.validateSelector(selector); err != nil {
+		return err
+	}
+	_, err := retryAttempts(opts, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, clickAttempt(page.Context(ctx), selector, opts, false)
+	})
+	if err != nil {
+		return fmt.Errorf("all click attempts failed: %w", err)
+	}
+	return nil
+}
 
-	for i := 0; i <= opts.RetryCount; i++ {
+// clickAttempt runs a single attempt of SafeClick on the page, which is bound to the attempt context.
+// It looks the element up, optionally waits for it to be enabled, waits for it to stabilize,
+// checks that no overlay intercepts the click if opts.CheckClickable is set, and clicks it.
+// It returns an error describing the step that failed.
+func clickAttempt(page *rod.Page, selector string, opts *RodOptions, waitEnabled bool) error {
+	// Wait for element
+	el, err := page.Element(selector)
+	if err != nil {
+		return fmt.Errorf("element not found: %w", err)
+	}
 
-		// If an error occurs, wait a bit and then retry
-		if i > 0 {
-			time.Sleep(opts.RetryDelay)
+	// // Check visibility
+	// if err := el.WaitVisible(); err != nil {
+	// 	return fmt.Errorf("element not visible: %w", err)
+	// }
+
+	// Wait for element to be enabled (optional)
+	if waitEnabled {
+		if err := el.WaitEnabled(); err != nil {
+			return fmt.Errorf("element not enabled: %w", err)
 		}
+	}
 
-		// Timeout context
-		ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
-		defer cancel()
+	// Wait for element to stabilize
+	if err := opts.waitStable(el); err != nil {
+		return fmt.Errorf("element not stable: %w", err)
+	}
 
-		// Wait for element
-		el, err := page.Context(ctx).Element(selector)
+	// Check that no overlay intercepts the click (optional)
+	if opts.CheckClickable {
+		if err := el.ScrollIntoView(); err != nil {
+			return fmt.Errorf("failed to scroll element into view: %w", err)
+		}
+		clickable, err := IsClickable(el)
 		if err != nil {
-			lastErr = fmt.Errorf("element not found: %w", err)
-			continue
+			return err
+		}
+		if !clickable {
+			dismissInterceptors(page, opts.DismissInterceptors)
+			return fmt.Errorf("%w: %s", ErrElementObscured, selector)
 		}
+	}
 
-		// // Check visibility
-		// if err := el.WaitVisible(); err != nil {
-		// 	lastErr = fmt.Errorf("element not visible: %w", err)
-		// 	continue
-		// }
+	// Execute click
+	err = withMouse(page, func() error {
+		return el.Click(proto.InputMouseButtonLeft, 1)
+	})
+	if err != nil {
+		return fmt.Errorf("click failed: %w", err)
+	}
+	return nil
+}
 
-		// Wait for element to stabilize
-		if err := el.WaitStable(opts.StableDuration); err != nil {
-			lastErr = fmt.Errorf("element not stable: %w", err)
-			continue
-		}
-		// Execute click
-		if err := el.Click(proto.InputMouseButtonLeft, 1); err != nil {
-			lastErr = fmt.Errorf("click failed: %w", err)
+// dismissInterceptors clicks the visible elements matching the overlay selectors.
+// It is best effort: missing overlays and failed clicks are ignored, since the next attempt checks the element again.
+func dismissInterceptors(p *rod.Page, selectors []string) {
+	for _, selector := range selectors {
+		has, el, err := p.Has(selector)
+		if err != nil || !has {
 			continue
 		}
-
-		// Exit the loop if successful
-		if lastErr == nil {
-			return nil
+		if visible, err := el.Visible(); err == nil && visible {
+			_ = withMouse(p, func() error {
+				return el.Click(proto.InputMouseButtonLeft, 1)
+			})
 		}
 	}
-
-	return fmt.Errorf("all click attempts failed: %w", lastErr)
 }
 
 // SafeElement retrieves an element safely.
@@ -191,46 +303,1391 @@ func SafeElement(p *rod.Page, selector string, opts *RodOptions) (*rod.Element,
 	if opts == nil {
 		opts = DefaultRodOptions()
 	}
-	var lastErr error
-	var element *rod.Element
-
-	for i := 0; i <= opts.RetryCount; i++ {
-		if i > 0 {
-			time.Sleep(opts.RetryDelay)
-		}
-
-		// Timeout context
-		ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
-		defer cancel()
-
+	if err := opts.validateSelector(selector); err != nil {
+		return nil, err
+	}
+	element, err := retryElement(p, opts, func(ctx context.Context) (*rod.Element, error) {
 		// Wait for element
 		el, err := p.Context(ctx).Element(selector)
 		if err != nil {
-			lastErr = fmt.Errorf("element not found: %w", err)
-			continue
+			return nil, fmt.Errorf("element not found: %w", err)
 		}
 
 		// Visibility check (optional)
 		if opts.MustVisible {
 			if err := el.WaitVisible(); err != nil {
-				lastErr = fmt.Errorf("element not visible: %w", err)
-				continue
+				return nil, fmt.Errorf("element not visible: %w", err)
 			}
 		}
 
 		// Stability check (optional)
 		if opts.MustStable {
-			if err := el.WaitStable(opts.StableDuration); err != nil {
-				lastErr = fmt.Errorf("element not stable: %w", err)
-				continue
+			if err := opts.waitStable(el); err != nil {
+				return nil, fmt.Errorf("element not stable: %w", err)
+			}
+		}
+		return el, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("all attempts to get element failed: %w", err)
+	}
+	return element, nil
+}
+
+// WaitPresent waits for the first element matching the selector to be present in the page's DOM.
+// It returns the element as soon as it exists, skipping the visibility and stability checks regardless of opts.
+// If the element does not appear within opts.Timeout, it returns an error.
+func WaitPresent(p *rod.Page, selector string, opts *RodOptions) (*rod.Element, error) {
+	if p == nil {
+		return nil, fmt.Errorf("rod.Page is nil")
+	}
+	if opts == nil {
+		opts = DefaultRodOptions()
+	}
+	if err := opts.validateSelector(selector); err != nil {
+		return nil, err
+	}
+
+	// Timeout context
+	ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
+	defer cancel()
+
+	var elem *rod.Element
+	err := pollUntil(ctx, opts.RetryDelay, func() (bool, error) {
+		has, el, err := p.Context(ctx).Has(selector)
+		if err != nil {
+			return false, err
+		}
+		elem = el
+		return has, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("element not present: %s\n%w", selector, err)
+	}
+
+	// Detach the element from the timeout context so it stays usable
+	return elem.Context(p.GetContext()), nil
+}
+
+// WaitAttributeValue retrieves an element safely and waits for the attribute with the given name to be set.
+// It returns the attribute value and an error, if any.
+// If the element is not found or the attribute is still unset after opts.Timeout, it returns an error.
+func WaitAttributeValue(p *rod.Page, selector, attr string, opts *RodOptions) (string, error) {
+	if opts == nil {
+		opts = DefaultRodOptions()
+	}
+	elem, err := SafeElement(p, selector, opts)
+	if err != nil {
+		return "", err
+	}
+
+	// Timeout context
+	ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
+	defer cancel()
+
+	var value string
+	err = pollUntil(ctx, opts.RetryDelay, func() (bool, error) {
+		v, err := elem.Attribute(attr)
+		if err != nil {
+			return false, err
+		}
+		if v == nil {
+			return false, nil
+		}
+		value = *v
+		return true, nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("attribute not set: %s[%s]\n%w", selector, attr, err)
+	}
+	return value, nil
+}
+
+// WaitRerendered waits for the element matching the selector to be replaced by a new node.
+// It captures the identity of the current node and returns the fresh element once a different node matches.
+// Nodes are compared by their backend node id, since remote object ids differ on every lookup.
+// If the element is not found or not replaced within opts.Timeout, it returns an error.
+func WaitRerendered(p *rod.Page, selector string, opts *RodOptions) (*rod.Element, error) {
+	if p == nil {
+		return nil, fmt.Errorf("rod.Page is nil")
+	}
+	if opts == nil {
+		opts = DefaultRodOptions()
+	}
+	if err := opts.validateSelector(selector); err != nil {
+		return nil, err
+	}
+
+	// Timeout context
+	ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
+	defer cancel()
+
+	old, err := p.Context(ctx).Element(selector)
+	if err != nil {
+		return nil, fmt.Errorf("element not found: %s\n%w", selector, err)
+	}
+	oldNode, err := old.Describe(0, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe element: %s\n%w", selector, err)
+	}
+
+	var elem *rod.Element
+	err = pollUntil(ctx, opts.RetryDelay, func() (bool, error) {
+		has, el, err := p.Context(ctx).Has(selector)
+		if err != nil || !has {
+			return false, err
+		}
+		node, err := el.Describe(0, false)
+		if err != nil {
+			// The node may have been replaced between the lookup and the describe call
+			return false, nil
+		}
+		if node.BackendNodeID == oldNode.BackendNodeID {
+			return false, nil
+		}
+		elem = el
+		return true, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("element not re-rendered: %s\n%w", selector, err)
+	}
+
+	// Detach the element from the timeout context so it stays usable
+	return elem.Context(p.GetContext()), nil
+}
+
+// interstitialJS reports which of the given markers are still present on the page.
+// A marker matches if it appears in the title or body text, or if it is a selector matching an element.
+const interstitialJS = `(markers) => {
+	const text = document.body ? document.body.innerText : '';
+	const found = markers.filter(m => {
+		if (document.title.includes(m) || text.includes(m)) return true;
+		try { return document.querySelector(m) !== null; } catch (e) { return false; }
+	});
+	const ready = document.readyState === 'complete' && !!document.body && document.body.childElementCount > 0;
+	return { found, ready };
+}`
+
+// WaitInterstitialGone waits for an interstitial page, such as a browser check, to be replaced by the real content.
+// Each marker is matched against the title and body text, and as a selector against the document.
+// It resolves once no marker is present and the document has finished loading with a non-empty body.
+// If the interstitial is still present after opts.Timeout, it returns an error.
+func WaitInterstitialGone(p *rod.Page, markers []string, opts *RodOptions) error {
+	if p == nil {
+		return fmt.Errorf("rod.Page is nil")
+	}
+	if opts == nil {
+		opts = DefaultRodOptions()
+	}
+
+	// Timeout context
+	ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
+	defer cancel()
+
+	var found []string
+	err := pollUntil(ctx, opts.RetryDelay, func() (bool, error) {
+		res, err := p.Context(ctx).Eval(interstitialJS, markers)
+		if err != nil {
+			// The interstitial may be navigating away while we evaluate
+			return false, nil
+		}
+		found = nil
+		for _, m := range res.Value.Get("found").Arr() {
+			found = append(found, m.Str())
+		}
+		return len(found) == 0 && res.Value.Get("ready").Bool(), nil
+	})
+	if err != nil {
+		return fmt.Errorf("interstitial still present: %v\n%w", found, err)
+	}
+	return nil
+}
+
+// ActiveElement returns the element that currently has focus in the page.
+// It returns the element and an error, if any.
+// If no element has focus, including when focus rests on the body, it returns an error without waiting.
+func ActiveElement(p *rod.Page) (*rod.Element, error) {
+	if p == nil {
+		return nil, fmt.Errorf("rod.Page is nil")
+	}
+	elem, err := focusedElement(p)
+	if err != nil {
+		return nil, err
+	}
+	if elem == nil {
+		return nil, errors.New("no element has focus")
+	}
+	return elem, nil
+}
+
+// focusedElement resolves the focused element of the page in a single evaluation, without waiting.
+// It returns nil without an error when focus rests on the body or nowhere, which is what browsers report when nothing is focused.
+func focusedElement(p *rod.Page) (*rod.Element, error) {
+	obj, err := p.Evaluate(rod.Eval(`() => {
+		const el = document.activeElement;
+		return el && el !== document.body ? el : null;
+	}`).ByObject())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active element: %v", err)
+	}
+	if obj.ObjectID == "" {
+		return nil, nil
+	}
+	elem, err := p.ElementFromObject(obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active element: %v", err)
+	}
+	return elem, nil
+}
+
+// TabTo presses Tab the given number of times from the current focus.
+// It returns the element that has focus afterwards and an error, if any.
+// If a key press fails or no element has focus, it returns an error.
+func TabTo(p *rod.Page, times int) (*rod.Element, error) {
+	if p == nil {
+		return nil, fmt.Errorf("rod.Page is nil")
+	}
+	for i := 0; i < times; i++ {
+		if err := p.Keyboard.Type(input.Tab); err != nil {
+			return nil, fmt.Errorf("failed to press tab: %v", err)
+		}
+	}
+	return ActiveElement(p)
+}
+
+// HandleFileChooser supplies files to the native file chooser opened by trigger.
+// It intercepts the file chooser before running trigger, so use it when the file input cannot be targeted directly.
+// It returns an error if trigger fails, if no file chooser opens within DefaultTimeoutDuration, or if setting the files fails.
+// The interception is disabled again on every return path.
+func HandleFileChooser(page *rod.Page, trigger func() error, paths ...string) error {
+	if page == nil {
+		return fmt.Errorf("rod.Page is nil")
+	}
+	absPaths := make([]string, 0, len(paths))
+	for _, path := range paths {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return fmt.Errorf("failed to resolve file path: %s\n%v", path, err)
+		}
+		absPaths = append(absPaths, abs)
+	}
+
+	err := proto.PageSetInterceptFileChooserDialog{Enabled: true}.Call(page)
+	if err != nil {
+		return fmt.Errorf("failed to intercept file chooser: %v", err)
+	}
+	defer func() {
+		_ = proto.PageSetInterceptFileChooserDialog{Enabled: false}.Call(page)
+	}()
+
+	// Timeout context
+	ctx, cancel := context.WithTimeout(page.GetContext(), DefaultTimeoutDuration)
+	defer cancel()
+
+	var e proto.PageFileChooserOpened
+	wait := page.Context(ctx).WaitEvent(&e)
+
+	if err := trigger(); err != nil {
+		return fmt.Errorf("failed to trigger file chooser: %w", err)
+	}
+	wait()
+	if ctx.Err() != nil {
+		return fmt.Errorf("file chooser did not open: %w", ctx.Err())
+	}
+
+	err = proto.DOMSetFileInputFiles{Files: absPaths, BackendNodeID: e.BackendNodeID}.Call(page)
+	if err != nil {
+		return fmt.Errorf("failed to set files: %v", err)
+	}
+	return nil
+}
+
+// NavTiming holds the navigation timing metrics of a page load.
+// Each duration is measured from the start of the navigation unless stated otherwise.
+type NavTiming struct {
+	DNS              time.Duration // Time spent on the DNS lookup
+	TCP              time.Duration // Time spent establishing the connection
+	TTFB             time.Duration // Time to the first byte of the response
+	DOMContentLoaded time.Duration // Time until the DOMContentLoaded event finished
+	Load             time.Duration // Time until the load event finished
+}
+
+// NavigationTiming reads the navigation timing metrics of the current document from the Performance API.
+// It returns the metrics and an error, if any.
+// If no navigation entry exists yet, it returns an error.
+func NavigationTiming(p *rod.Page) (*NavTiming, error) {
+	if p == nil {
+		return nil, fmt.Errorf("rod.Page is nil")
+	}
+	res, err := p.Eval(`() => {
+		const [t] = performance.getEntriesByType('navigation');
+		if (!t) return null;
+		return {
+			dns: t.domainLookupEnd - t.domainLookupStart,
+			tcp: t.connectEnd - t.connectStart,
+			ttfb: t.responseStart - t.startTime,
+			domContentLoaded: t.domContentLoadedEventEnd - t.startTime,
+			load: t.loadEventEnd - t.startTime,
+		};
+	}`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get navigation timing: %v", err)
+	}
+	if res.Value.Nil() {
+		return nil, fmt.Errorf("no navigation timing entry found")
+	}
+
+	ms := func(key string) time.Duration {
+		return time.Duration(res.Value.Get(key).Num() * float64(time.Millisecond))
+	}
+	return &NavTiming{
+		DNS:              ms("dns"),
+		TCP:              ms("tcp"),
+		TTFB:             ms("ttfb"),
+		DOMContentLoaded: ms("domContentLoaded"),
+		Load:             ms("load"),
+	}, nil
+}
+
+// WaitTitle waits for the page title to match the regular expression pattern.
+// It returns an error immediately if the pattern is invalid.
+// If the title does not match within opts.Timeout, it returns an error including the last seen title.
+func WaitTitle(p *rod.Page, pattern string, opts *RodOptions) error {
+	if p == nil {
+		return fmt.Errorf("rod.Page is nil")
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("invalid title pattern: %s\n%v", pattern, err)
+	}
+	if opts == nil {
+		opts = DefaultRodOptions()
+	}
+
+	// Timeout context
+	ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
+	defer cancel()
+
+	var title string
+	err = pollUntil(ctx, opts.RetryDelay, func() (bool, error) {
+		info, err := p.Context(ctx).Info()
+		if err != nil {
+			return false, fmt.Errorf("failed to get page info: %v", err)
+		}
+		title = info.Title
+		return re.MatchString(title), nil
+	})
+	if err != nil {
+		return fmt.Errorf("title does not match: %s (last seen: %q)\n%w", pattern, title, err)
+	}
+	return nil
+}
+
+// AwaitJS evaluates the JavaScript expression in the page, awaits the promise it returns, and decodes the resolved value into T.
+// Non-promise values are decoded as they are.
+// It returns the decoded value and an error, if any.
+// If the promise rejects, the error carries the rejection reason; if it does not settle within opts.Timeout, it returns a timeout error.
+func AwaitJS[T any](p *rod.Page, expression string, opts *RodOptions) (T, error) {
+	var result T
+	if p == nil {
+		return result, fmt.Errorf("rod.Page is nil")
+	}
+	if opts == nil {
+		opts = DefaultRodOptions()
+	}
+
+	// Timeout context
+	ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
+	defer cancel()
+
+	res, err := p.Context(ctx).Eval(fmt.Sprintf("() => (%s)", expression))
+	if err != nil {
+		var evalErr *rod.EvalError
+		if errors.As(err, &evalErr) && evalErr.Exception != nil {
+			reason := evalErr.Exception.Description
+			if reason == "" {
+				reason = evalErr.Exception.Value.String()
 			}
+			return result, fmt.Errorf("promise rejected: %s\n%w", reason, err)
 		}
+		if ctx.Err() != nil {
+			return result, fmt.Errorf("promise did not settle: %s\n%w", expression, ctx.Err())
+		}
+		return result, fmt.Errorf("failed to evaluate expression: %s\n%w", expression, err)
+	}
+	if err := res.Value.Unmarshal(&result); err != nil {
+		return result, fmt.Errorf("failed to decode result: %s\n%w", expression, err)
+	}
+	return result, nil
+}
+
+// WaitClass waits for the class list of the element matching the selector to contain the class, or to omit it if present is false.
+// It returns an error if the element is not found or if the class list does not reach the expected state within opts.Timeout.
+// The timeout error includes the last seen class list.
+func WaitClass(p *rod.Page, selector, className string, present bool, opts *RodOptions) error {
+	if p == nil {
+		return fmt.Errorf("rod.Page is nil")
+	}
+	if opts == nil {
+		opts = DefaultRodOptions()
+	}
+	if err := opts.validateSelector(selector); err != nil {
+		return err
+	}
 
-		// If all checks pass
-		element = el
+	// Timeout context
+	ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
+	defer cancel()
 
-		return element, lastErr
+	var classes string
+	err := pollUntil(ctx, opts.RetryDelay, func() (bool, error) {
+		has, el, err := p.Context(ctx).Has(selector)
+		if err != nil || !has {
+			return false, err
+		}
+		res, err := el.Eval(`(name) => ({ classes: this.getAttribute('class') || '', has: this.classList.contains(name) })`, className)
+		if err != nil {
+			// The element may have been replaced between the lookup and the evaluation
+			return false, nil
+		}
+		classes = res.Value.Get("classes").Str()
+		return res.Value.Get("has").Bool() == present, nil
+	})
+	if err != nil {
+		state := "present"
+		if !present {
+			state = "absent"
+		}
+		return fmt.Errorf("class %s is not %s: %s (last seen: %q)\n%w", className, state, selector, classes, err)
 	}
+	return nil
+}
 
-	return nil, fmt.Errorf("all attempts to get element failed: %w", lastErr)
+// WaitVisibleBool waits for the element matching the selector to become visible.
+// It returns true if the element becomes visible within opts.Timeout, and false without an error if the timeout passes.
+// It returns an error only on genuine failures, such as a nil page or an invalid selector.
+func WaitVisibleBool(p *rod.Page, selector string, opts *RodOptions) (bool, error) {
+	if p == nil {
+		return false, fmt.Errorf("rod.Page is nil")
+	}
+	if opts == nil {
+		opts = DefaultRodOptions()
+	}
+	if err := opts.validateSelector(selector); err != nil {
+		return false, err
+	}
+
+	// Timeout context
+	ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
+	defer cancel()
+
+	err := pollUntil(ctx, opts.RetryDelay, func() (bool, error) {
+		has, el, err := p.Context(ctx).Has(selector)
+		if err != nil {
+			return false, fmt.Errorf("failed to check element existence: %s\n%w", selector, err)
+		}
+		if !has {
+			return false, nil
+		}
+		visible, err := el.Visible()
+		if err != nil {
+			// The element may have been replaced between the lookup and the check
+			return false, nil
+		}
+		return visible, nil
+	})
+	if errors.Is(err, context.DeadlineExceeded) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// WaitModal waits for the modal matching modalSelector to be visible and stable, then finds innerSelector within it.
+// Both stages share opts.Timeout, and the error names the stage that failed.
+// It returns the inner element and an error, if any.
+func WaitModal(p *rod.Page, modalSelector, innerSelector string, opts *RodOptions) (*rod.Element, error) {
+	if p == nil {
+		return nil, fmt.Errorf("rod.Page is nil")
+	}
+	if opts == nil {
+		opts = DefaultRodOptions()
+	}
+	if err := opts.validateSelector(modalSelector, innerSelector); err != nil {
+		return nil, err
+	}
+
+	// Timeout context
+	ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
+	defer cancel()
+
+	var modal *rod.Element
+	err := pollUntil(ctx, opts.RetryDelay, func() (bool, error) {
+		has, el, err := p.Context(ctx).Has(modalSelector)
+		if err != nil || !has {
+			return false, err
+		}
+		visible, err := el.Visible()
+		if err != nil || !visible {
+			return false, nil
+		}
+		modal = el
+		return true, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("modal not visible: %s\n%w", modalSelector, err)
+	}
+	if err := modal.WaitStable(opts.StableDuration); err != nil {
+		return nil, fmt.Errorf("modal not stable: %s\n%w", modalSelector, err)
+	}
+
+	var inner *rod.Element
+	err = pollUntil(ctx, opts.RetryDelay, func() (bool, error) {
+		has, el, err := modal.Has(innerSelector)
+		if err != nil {
+			return false, err
+		}
+		inner = el
+		return has, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("element not found in modal %s: %s\n%w", modalSelector, innerSelector, err)
+	}
+
+	// Detach the element from the timeout context so it stays usable
+	return inner.Context(p.GetContext()), nil
+}
+
+// WaitFontsReady waits for the web fonts of the page to finish loading, using the Font Loading API.
+// It resolves immediately if the browser does not support document.fonts.
+// It returns an error if the fonts have not loaded within opts.Timeout.
+func WaitFontsReady(p *rod.Page, opts *RodOptions) error {
+	if p == nil {
+		return fmt.Errorf("rod.Page is nil")
+	}
+	if opts == nil {
+		opts = DefaultRodOptions()
+	}
+
+	// Timeout context
+	ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
+	defer cancel()
+
+	_, err := p.Context(ctx).Eval(`() => document.fonts ? document.fonts.ready.then(() => true) : true`)
+	if err != nil {
+		return fmt.Errorf("failed to wait for fonts to load: %w", err)
+	}
+	return nil
+}
+
+// HoverPath hovers each selector in order, waiting for the next one to become visible before moving on.
+// It keeps the mouse within the chain, so nested flyout menus stay open, and returns the final element.
+// All levels share opts.Timeout, and the error names the level that failed to appear.
+func HoverPath(p *rod.Page, selectors []string, opts *RodOptions) (*rod.Element, error) {
+	if p == nil {
+		return nil, fmt.Errorf("rod.Page is nil")
+	}
+	if len(selectors) == 0 {
+		return nil, fmt.Errorf("no selectors to hover")
+	}
+	if opts == nil {
+		opts = DefaultRodOptions()
+	}
+	if err := opts.validateSelector(selectors...); err != nil {
+		return nil, err
+	}
+
+	// Keep the mouse on the path, so no background click closes a menu level in between
+	unlock := lockMouse(p)
+	defer unlock()
+
+	// Timeout context
+	ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
+	defer cancel()
+
+	var elem *rod.Element
+	for i, selector := range selectors {
+		err := pollUntil(ctx, opts.RetryDelay, func() (bool, error) {
+			has, el, err := p.Context(ctx).Has(selector)
+			if err != nil || !has {
+				return false, err
+			}
+			visible, err := el.Visible()
+			if err != nil || !visible {
+				return false, nil
+			}
+			elem = el
+			return true, nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("menu level %d did not appear: %s\n%w", i+1, selector, err)
+		}
+		if err := elem.Hover(); err != nil {
+			return nil, fmt.Errorf("failed to hover menu level %d: %s\n%w", i+1, selector, err)
+		}
+	}
+
+	// Detach the element from the timeout context so it stays usable
+	return elem.Context(p.GetContext()), nil
+}
+
+// mouseLocks holds the mutex that serializes the mouse input of each target, keyed by proto.TargetTargetID.
+// An entry is removed once the browser reports its target destroyed.
+var mouseLocks sync.Map
+
+// lockMouse takes the mouse lock of the page's target, shared by its frames, and returns the function that releases it.
+// Helpers hold it for the whole of a multi-step mouse action, so the clicks of AutoDismiss cannot land in the middle of it.
+// The lock is not reentrant, so a helper holding it must not call another helper that takes it.
+func lockMouse(p *rod.Page) (unlock func()) {
+	mu, loaded := mouseLocks.LoadOrStore(p.TargetID, &sync.Mutex{})
+	if !loaded {
+		pruneMouseLock(p)
+	}
+	m := mu.(*sync.Mutex)
+	m.Lock()
+	return m.Unlock
+}
+
+// pruneMouseLock removes the mouse lock of the page's target from mouseLocks when the target is destroyed, such as when the page closes.
+// The entry is also removed if the browser disconnects first.
+// Pages without a browser keep their entry.
+func pruneMouseLock(p *rod.Page) {
+	browser := p.Browser()
+	if browser == nil {
+		return
+	}
+	targetID := p.TargetID
+	wait := browser.EachEvent(func(e *proto.TargetTargetDestroyed) bool {
+		return e.TargetID == targetID
+	})
+	go func() {
+		wait()
+		mouseLocks.Delete(targetID)
+	}()
+}
+
+// withMouse runs the mouse action while holding the mouse lock of the page.
+func withMouse(p *rod.Page, action func() error) error {
+	unlock := lockMouse(p)
+	defer unlock()
+	return action()
+}
+
+// AutoDismiss clicks any visible element matching one of the selectors, such as a popup close button, every interval.
+// It runs in a background goroutine until stop is called, so long flows are not blocked by unexpected popups.
+// Checks never overlap, and stop waits for the check in progress to finish, so no click happens after stop returns.
+// Each click takes the page's mouse lock, like the mouse-driving helpers of this package, such as SafeClick, HoverPath and ContextMenuClick,
+// so a dismiss click never lands in the middle of one of their mouse actions.
+// Mouse input sent directly through rod is not covered and can interleave with the clicks.
+func AutoDismiss(page *rod.Page, selectors []string, interval time.Duration) (stop func()) {
+	if page == nil || len(selectors) == 0 {
+		return func() {}
+	}
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	ctx, cancel := context.WithCancel(page.GetContext())
+	p := page.Context(ctx)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			for _, selector := range selectors {
+				has, el, err := p.Has(selector)
+				if err != nil || !has {
+					continue
+				}
+				if visible, err := el.Visible(); err == nil && visible {
+					_ = withMouse(p, func() error {
+						return el.Click(proto.InputMouseButtonLeft, 1)
+					})
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			cancel()
+			<-done
+		})
+	}
+}
+
+// pageStateJS returns the URL of the page and a hash of its visible text.
+const pageStateJS = `() => {
+	const text = document.body ? document.body.innerText : '';
+	let hash = 0;
+	for (let i = 0; i < text.length; i++) hash = (hash * 31 + text.charCodeAt(i)) | 0;
+	return location.href + '#' + hash;
+}`
+
+// NavigateAndVerify runs trigger, such as clicking a "next" link, and waits for the page to actually change.
+// The page counts as changed once its URL or the hash of its visible text differs from before the trigger.
+// It returns an error if trigger fails or if nothing changed within opts.Timeout, which ends pagination loops on the last page.
+func NavigateAndVerify(p *rod.Page, trigger func() error, opts *RodOptions) error {
+	if p == nil {
+		return fmt.Errorf("rod.Page is nil")
+	}
+	if opts == nil {
+		opts = DefaultRodOptions()
+	}
+	before, err := p.Eval(pageStateJS)
+	if err != nil {
+		return fmt.Errorf("failed to get page state: %v", err)
+	}
+	if err := trigger(); err != nil {
+		return fmt.Errorf("failed to trigger navigation: %w", err)
+	}
+
+	// Timeout context
+	ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
+	defer cancel()
+
+	err = pollUntil(ctx, opts.RetryDelay, func() (bool, error) {
+		after, err := p.Context(ctx).Eval(pageStateJS)
+		if err != nil {
+			// The page may be between documents while navigating
+			return false, nil
+		}
+		return after.Value.Str() != before.Value.Str(), nil
+	})
+	if err != nil {
+		return fmt.Errorf("navigation did not change the page: %w", err)
+	}
+	return nil
+}
+
+// WaitTextMatch waits for the text of the element matching the selector to match the regular expression pattern.
+// It returns the match like regexp.FindStringSubmatch, with the whole match at index 0 followed by the captured groups.
+// It returns an error immediately if the pattern is invalid.
+// If the text does not match within opts.Timeout, it returns an error including the last seen text.
+func WaitTextMatch(p *rod.Page, selector, pattern string, opts *RodOptions) ([]string, error) {
+	if p == nil {
+		return nil, fmt.Errorf("rod.Page is nil")
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid text pattern: %s\n%v", pattern, err)
+	}
+	if opts == nil {
+		opts = DefaultRodOptions()
+	}
+	if err := opts.validateSelector(selector); err != nil {
+		return nil, err
+	}
+
+	// Timeout context
+	ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
+	defer cancel()
+
+	var text string
+	var match []string
+	err = pollUntil(ctx, opts.RetryDelay, func() (bool, error) {
+		has, el, err := p.Context(ctx).Has(selector)
+		if err != nil || !has {
+			return false, err
+		}
+		t, err := el.Text()
+		if err != nil {
+			// The element may have been replaced between the lookup and the read
+			return false, nil
+		}
+		text = t
+		match = re.FindStringSubmatch(text)
+		return match != nil, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("text does not match: %s %s (last seen: %q)\n%w", selector, pattern, text, err)
+	}
+	return match, nil
+}
+
+// VisitEach navigates the page to each URL in order and invokes onVisit once the page is ready.
+// Each navigation is limited by opts.Timeout and waits for the page load when opts.MustWaitLoad is set.
+// A failed visit is captured as a screenshot following the RodOperationWrapper conventions.
+// It continues past failed visits and returns their combined errors, or stops at the first one if opts.StopOnError is set.
+func VisitEach(p *rod.Page, urls []string, opts *RodOptions, onVisit func(url string, p *rod.Page) error) error {
+	if p == nil {
+		return fmt.Errorf("rod.Page is nil")
+	}
+	if opts == nil {
+		opts = DefaultRodOptions()
+	}
+	var errs []error
+
+	for i, url := range urls {
+		err := visit(p, url, opts, onVisit)
+		if err == nil {
+			continue
+		}
+		err = fmt.Errorf("failed to visit: %s\n%w", url, err)
+
+		// Keep a screenshot of the failed visit for debugging
+		name := fmt.Sprintf("visit-%d", i+1)
+		settings := resolveWrapperOptions(&RodOperationWrapperOptions{Name: &name})
+		screenshotName := fmt.Sprintf("%s/%s.png", settings.path, settings.name)
+		if screenErr := settings.screenshot(p, screenshotName); screenErr != nil {
+			err = combineErrors([]error{err, screenErr})
+		}
+
+		errs = append(errs, err)
+		if opts.StopOnError {
+			break
+		}
+	}
+
+	return combineErrors(errs)
+}
+
+// visit navigates the page to the URL, waits for it according to opts, and invokes onVisit.
+func visit(p *rod.Page, url string, opts *RodOptions, onVisit func(url string, p *rod.Page) error) error {
+	page := p.Timeout(opts.Timeout)
+	defer page.CancelTimeout()
+
+	if _, err := Navigate(page, url); err != nil {
+		return err
+	}
+	if opts.MustWaitLoad {
+		if err := page.WaitLoad(); err != nil {
+			return fmt.Errorf("error waiting for page load to complete: %v", err)
+		}
+	}
+	return onVisit(url, p)
+}
+
+// LoginIndicators lists the caller-supplied markers that reveal whether a page shows a logged-in session.
+// Selectors match elements, texts match the visible body text, and URLs match as substrings of the page URL.
+type LoginIndicators struct {
+	LoggedInSelectors  []string // Elements only shown when logged in, such as a user menu
+	LoggedInTexts      []string // Texts only shown when logged in, such as "Sign out"
+	LoggedOutSelectors []string // Elements only shown when logged out, such as a login form
+	LoggedOutTexts     []string // Texts only shown when logged out, such as "Sign in"
+	LoggedOutURLs      []string // URL fragments of the login wall, such as "/login"
+}
+
+// IsLoggedIn reports whether the page shows a logged-in session according to the indicators.
+// Logged-out markers take precedence, so a login wall is detected even if a stale logged-in marker remains.
+// It returns an error if the markers cannot be checked or if no marker of either kind is present.
+func IsLoggedIn(p *rod.Page, indicators LoginIndicators) (bool, error) {
+	if p == nil {
+		return false, fmt.Errorf("rod.Page is nil")
+	}
+	info, err := p.Info()
+	if err != nil {
+		return false, fmt.Errorf("failed to get page info: %v", err)
+	}
+	for _, fragment := range indicators.LoggedOutURLs {
+		if strings.Contains(info.URL, fragment) {
+			return false, nil
+		}
+	}
+
+	res, err := p.Eval(`(inSelectors, inTexts, outSelectors, outTexts) => {
+		const text = document.body ? document.body.innerText : '';
+		const matches = (selectors, texts) =>
+			(selectors || []).some(s => document.querySelector(s) !== null) ||
+			(texts || []).some(t => text.includes(t));
+		return { in: matches(inSelectors, inTexts), out: matches(outSelectors, outTexts) };
+	}`, indicators.LoggedInSelectors, indicators.LoggedInTexts, indicators.LoggedOutSelectors, indicators.LoggedOutTexts)
+	if err != nil {
+		return false, fmt.Errorf("failed to check login indicators: %v", err)
+	}
+	switch {
+	case res.Value.Get("out").Bool():
+		return false, nil
+	case res.Value.Get("in").Bool():
+		return true, nil
+	default:
+		return false, fmt.Errorf("login state could not be determined: no indicator matched on %s", info.URL)
+	}
+}
+
+// HoverAndReadTooltip hovers the target element, waits for the tooltip to appear and stabilize, and returns its text.
+// The text is read while the mouse still rests on the target, since many tooltips only exist in the DOM while hovered.
+// It returns an error if the target is not found or if the tooltip does not appear within opts.Timeout.
+func HoverAndReadTooltip(p *rod.Page, targetSelector, tooltipSelector string, opts *RodOptions) (string, error) {
+	if opts == nil {
+		opts = DefaultRodOptions()
+	}
+	if err := opts.validateSelector(targetSelector, tooltipSelector); err != nil {
+		return "", err
+	}
+	target, err := SafeElement(p, targetSelector, opts)
+	if err != nil {
+		return "", err
+	}
+
+	// Timeout context
+	ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
+	defer cancel()
+
+	if err := withMouse(p, target.Hover); err != nil {
+		return "", fmt.Errorf("failed to hover: %s\n%w", targetSelector, err)
+	}
+
+	var tooltip *rod.Element
+	err = pollUntil(ctx, opts.RetryDelay, func() (bool, error) {
+		has, el, err := p.Context(ctx).Has(tooltipSelector)
+		if err != nil || !has {
+			return false, err
+		}
+		visible, err := el.Visible()
+		if err != nil || !visible {
+			return false, nil
+		}
+		tooltip = el
+		return true, nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("tooltip did not appear: %s\n%w", tooltipSelector, err)
+	}
+	if err := tooltip.WaitStable(opts.StableDuration); err != nil {
+		return "", fmt.Errorf("tooltip not stable: %s\n%w", tooltipSelector, err)
+	}
+	text, err := tooltip.Text()
+	if err != nil {
+		return "", fmt.Errorf("failed to get text: %s\n%w", tooltipSelector, err)
+	}
+	return text, nil
+}
+
+// UniqueElement waits for the selector to match and returns the element only if exactly one element matches.
+// It catches ambiguous selectors early, before they silently act on the wrong element.
+// It returns an error reporting the count if more than one element matches, or if nothing matches within opts.Timeout.
+func UniqueElement(p *rod.Page, selector string, opts *RodOptions) (*rod.Element, error) {
+	elem, err := WaitPresent(p, selector, opts)
+	if err != nil {
+		return nil, err
+	}
+	elems, err := p.Elements(selector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get elements: %s\n%v", selector, err)
+	}
+	if len(elems) > 1 {
+		return nil, fmt.Errorf("selector is ambiguous, %d elements matched: %s", len(elems), selector)
+	}
+	return elem, nil
+}
+
+// FocusedElement returns the element that currently has focus in the page.
+// Unlike ActiveElement, it returns nil without an error when focus rests on the body or nowhere, so callers can tell that nothing is focused.
+// It returns an error if the focused element cannot be resolved.
+func FocusedElement(p *rod.Page) (*rod.Element, error) {
+	if p == nil {
+		return nil, fmt.Errorf("rod.Page is nil")
+	}
+	return focusedElement(p)
+}
+
+// WaitAttributePresent waits for the element matching the selector to have the attribute with the given name, whatever its value.
+// It suits presence-only flags such as data-loaded or open, where WaitAttributeValue's value is irrelevant.
+// It returns an error if the attribute is not set within opts.Timeout.
+func WaitAttributePresent(p *rod.Page, selector, attr string, opts *RodOptions) error {
+	if p == nil {
+		return fmt.Errorf("rod.Page is nil")
+	}
+	if opts == nil {
+		opts = DefaultRodOptions()
+	}
+	if err := opts.validateSelector(selector); err != nil {
+		return err
+	}
+
+	// Timeout context
+	ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
+	defer cancel()
+
+	err := pollUntil(ctx, opts.RetryDelay, func() (bool, error) {
+		has, el, err := p.Context(ctx).Has(selector)
+		if err != nil || !has {
+			return false, err
+		}
+		v, err := el.Attribute(attr)
+		if err != nil {
+			// The element may have been replaced between the lookup and the read
+			return false, nil
+		}
+		return v != nil, nil
+	})
+	if err != nil {
+		return fmt.Errorf("attribute not set: %s[%s]\n%w", selector, attr, err)
+	}
+	return nil
+}
+
+// ClickAndWaitFor clicks the element matching clickSelector with SafeClick, then waits for appearsSelector to become visible.
+// If the expected element does not appear within opts.Timeout, the click is retried up to opts.RetryCount times.
+// It returns the element that appeared and an error, if any, naming the stage that failed.
+func ClickAndWaitFor(p *rod.Page, clickSelector, appearsSelector string, opts *RodOptions) (*rod.Element, error) {
+	if p == nil {
+		return nil, fmt.Errorf("rod.Page is nil")
+	}
+	if opts == nil {
+		opts = DefaultRodOptions()
+	}
+	if err := opts.validateSelector(clickSelector, appearsSelector); err != nil {
+		return nil, err
+	}
+	var lastErr error
+
+	for i := 0; i <= opts.RetryCount; i++ {
+		if i > 0 {
+			if !opts.shouldRetry(lastErr) {
+				break
+			}
+			time.Sleep(opts.RetryDelay)
+		}
+
+		if err := SafeClick(p, clickSelector, opts); err != nil {
+			return nil, fmt.Errorf("click stage failed: %s\n%w", clickSelector, err)
+		}
+		elem, err := waitVisible(p, appearsSelector, opts)
+		if err != nil {
+			lastErr = fmt.Errorf("element did not appear after click: %s\n%w", appearsSelector, err)
+			continue
+		}
+		return elem, nil
+	}
+
+	return nil, fmt.Errorf("all click attempts failed to reveal element: %w", lastErr)
+}
+
+// waitVisible waits for the element matching the selector to be present and visible within opts.Timeout.
+// It returns the element detached from the timeout context.
+func waitVisible(p *rod.Page, selector string, opts *RodOptions) (*rod.Element, error) {
+	// Timeout context
+	ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
+	defer cancel()
+
+	var elem *rod.Element
+	err := pollUntil(ctx, opts.RetryDelay, func() (bool, error) {
+		has, el, err := p.Context(ctx).Has(selector)
+		if err != nil || !has {
+			return false, err
+		}
+		visible, err := el.Visible()
+		if err != nil || !visible {
+			return false, nil
+		}
+		elem = el
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return elem.Context(p.GetContext()), nil
+}
+
+// idleCallbackFallback is the delay used by WaitIdleCallback when requestIdleCallback is unsupported.
+const idleCallbackFallback = 50 * time.Millisecond
+
+// WaitIdleCallback waits for the main thread of the page to become idle, using requestIdleCallback.
+// It complements network idle waits for CPU-bound rendering. Where the API is unsupported, it resolves after a short delay instead.
+// It returns an error if the main thread does not become idle within opts.Timeout.
+func WaitIdleCallback(p *rod.Page, opts *RodOptions) error {
+	if p == nil {
+		return fmt.Errorf("rod.Page is nil")
+	}
+	if opts == nil {
+		opts = DefaultRodOptions()
+	}
+
+	// Timeout context
+	ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
+	defer cancel()
+
+	_, err := p.Context(ctx).Eval(`(fallback) => new Promise(resolve => {
+		if (typeof requestIdleCallback === 'function') {
+			requestIdleCallback(() => resolve(true));
+		} else {
+			setTimeout(() => resolve(false), fallback);
+		}
+	})`, idleCallbackFallback.Milliseconds())
+	if err != nil {
+		return fmt.Errorf("failed to wait for idle callback: %w", err)
+	}
+	return nil
+}
+
+// ContextMenuClick right-clicks the target element, waits for the app-rendered context menu item to be visible, and clicks it.
+// Only menus rendered in the DOM can be automated; the native browser context menu cannot.
+// It returns an error if the target is not found, or if the menu item does not appear within opts.Timeout,
+// which usually means the page showed the native menu instead of a custom one.
+func ContextMenuClick(p *rod.Page, targetSelector, menuItemSelector string, opts *RodOptions) error {
+	if opts == nil {
+		opts = DefaultRodOptions()
+	}
+	if err := opts.validateSelector(targetSelector, menuItemSelector); err != nil {
+		return err
+	}
+	target, err := SafeElement(p, targetSelector, opts)
+	if err != nil {
+		return err
+	}
+
+	// Keep the menu open until the item is clicked, so no background click dismisses it in between
+	unlock := lockMouse(p)
+	defer unlock()
+
+	if err := target.Click(proto.InputMouseButtonRight, 1); err != nil {
+		return fmt.Errorf("failed to right-click: %s\n%w", targetSelector, err)
+	}
+
+	item, err := waitVisible(p, menuItemSelector, opts)
+	if err != nil {
+		return fmt.Errorf("context menu item did not appear, the page may not render a custom context menu "+
+			"(the native browser menu cannot be automated): %s\n%w", menuItemSelector, err)
+	}
+	if err := item.Click(proto.InputMouseButtonLeft, 1); err != nil {
+		return fmt.Errorf("failed to click context menu item: %s\n%w", menuItemSelector, err)
+	}
+	return nil
+}
+
+// ActAndWaitGone runs the action, such as clicking a delete button, then waits for the selector to no longer match a visible element.
+// It confirms that a destructive action completed instead of assuming success.
+// It returns an error if the action fails or if the element is still present after opts.Timeout.
+func ActAndWaitGone(p *rod.Page, action func() error, selector string, opts *RodOptions) error {
+	if p == nil {
+		return fmt.Errorf("rod.Page is nil")
+	}
+	if opts == nil {
+		opts = DefaultRodOptions()
+	}
+	if err := opts.validateSelector(selector); err != nil {
+		return err
+	}
+	if err := action(); err != nil {
+		return fmt.Errorf("action failed: %w", err)
+	}
+
+	// Timeout context
+	ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
+	defer cancel()
+
+	err := pollUntil(ctx, opts.RetryDelay, func() (bool, error) {
+		has, el, err := p.Context(ctx).Has(selector)
+		if err != nil {
+			return false, err
+		}
+		if !has {
+			return true, nil
+		}
+		visible, err := el.Visible()
+		if err != nil {
+			// The element may have been removed between the lookup and the check
+			return false, nil
+		}
+		return !visible, nil
+	})
+	if err != nil {
+		return fmt.Errorf("element still present after action: %s\n%w", selector, err)
+	}
+	return nil
+}
+
+// Ping checks that the page still responds by evaluating a trivial script within the timeout.
+// Use it as a liveness check before an expensive operation, since a hung renderer otherwise stalls every call silently.
+// If timeout is not positive, DefaultTimeoutDuration is used.
+// It returns an error if the page does not respond in time.
+func Ping(p *rod.Page, timeout time.Duration) error {
+	if p == nil {
+		return fmt.Errorf("rod.Page is nil")
+	}
+	if timeout <= 0 {
+		timeout = DefaultTimeoutDuration
+	}
+
+	// Timeout context
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if _, err := p.Context(ctx).Eval(`() => 1`); err != nil {
+		return fmt.Errorf("page is not responding: %w", err)
+	}
+	return nil
+}
+
+// WaitEnabledAndClick waits for the element matching the selector to be enabled, then clicks it, retrying the whole sequence on failure.
+// Each attempt looks the element up again, so a button re-rendered by async validation is not clicked while still disabled.
+// Apart from the wait for the element to be enabled, each attempt runs the same steps as SafeClick and honors the same options.
+// Retries are limited by opts.RetryCount, and each attempt has its own opts.Timeout deadline.
+// It returns an error wrapping the last failure if every attempt fails.
+func WaitEnabledAndClick(p *rod.Page, selector string, opts *RodOptions) error {
+	if p == nil {
+		return fmt.Errorf("rod.Page is nil")
+	}
+	if opts == nil {
+		opts = DefaultRodOptions()
+	}
+	if err := opts.validateSelector(selector); err != nil {
+		return err
+	}
+
+	_, err := retryAttempts(opts, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, clickAttempt(p.Context(ctx), selector, opts, true)
+	})
+	if err != nil {
+		return fmt.Errorf("all click attempts failed: %s\n%w", selector, err)
+	}
+	return nil
+}
+
+// ScrollPosition returns the current scroll offsets of the page's window, in CSS pixels.
+// It returns the offsets and an error, if any.
+func ScrollPosition(p *rod.Page) (x, y float64, err error) {
+	if p == nil {
+		return 0, 0, fmt.Errorf("rod.Page is nil")
+	}
+	res, err := p.Eval(`() => [window.scrollX, window.scrollY]`)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get scroll position: %v", err)
+	}
+	pos := res.Value.Arr()
+	return pos[0].Num(), pos[1].Num(), nil
+}
+
+// scrollPositionTolerance is the distance in CSS pixels within which ScrollTo considers the target reached,
+// to absorb the subpixel rounding of scroll offsets.
+const scrollPositionTolerance = 1.0
+
+// ScrollTo instantly scrolls the page's window to the given offsets, in CSS pixels, and verifies the position after the next frame.
+// Smooth scrolling set by the page's CSS is bypassed, so the position is deterministic.
+// It returns an error if scrolling fails or the position cannot be reached, such as when the page is too short.
+func ScrollTo(p *rod.Page, x, y float64) error {
+	if p == nil {
+		return fmt.Errorf("rod.Page is nil")
+	}
+	res, err := p.Eval(`async (x, y) => {
+		window.scrollTo({ left: x, top: y, behavior: 'instant' });
+		await new Promise(resolve => requestAnimationFrame(resolve));
+		return [window.scrollX, window.scrollY];
+	}`, x, y)
+	if err != nil {
+		return fmt.Errorf("failed to scroll: %v", err)
+	}
+	pos := res.Value.Arr()
+	gotX, gotY := pos[0].Num(), pos[1].Num()
+	if math.Abs(gotX-x) > scrollPositionTolerance || math.Abs(gotY-y) > scrollPositionTolerance {
+		return fmt.Errorf("failed to reach scroll position %v,%v (settled at %v,%v)", x, y, gotX, gotY)
+	}
+	return nil
+}
+
+// SelectorMatchCount returns the number of elements in the page currently matching the selector, without waiting.
+// Zero matches is a valid count and is not an error.
+// It returns the count and an error, if any, such as for an invalid selector.
+func SelectorMatchCount(p *rod.Page, selector string) (int, error) {
+	if p == nil {
+		return 0, fmt.Errorf("rod.Page is nil")
+	}
+	res, err := p.Eval(`(selector) => document.querySelectorAll(selector).length`, selector)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count elements: %s\n%v", selector, err)
+	}
+	return res.Value.Int(), nil
+}
+
+// AssertSingleMatch checks that exactly one element in the page currently matches the selector, without waiting.
+// Use it during development to catch selectors that will become ambiguous as the page grows.
+// It returns an error reporting the count if no element or more than one element matches.
+func AssertSingleMatch(p *rod.Page, selector string) error {
+	count, err := SelectorMatchCount(p, selector)
+	if err != nil {
+		return err
+	}
+	if count != 1 {
+		return fmt.Errorf("expected exactly 1 element, %d matched: %s", count, selector)
+	}
+	return nil
+}
+
+// loadMoreMaxClicks caps the number of clicks of ClickLoadMore, guarding against buttons that never go away.
+const loadMoreMaxClicks = 100
+
+// loadMoreAvailableJS reports whether the load more button can still be clicked.
+const loadMoreAvailableJS = `() => {
+	const r = this.getBoundingClientRect();
+	const hidden = r.width === 0 && r.height === 0;
+	return !hidden && !this.disabled && this.getAttribute('aria-disabled') !== 'true';
+}`
+
+// ClickLoadMore clicks the "load more" button matching buttonSelector until it disappears or becomes disabled, up to 100 clicks.
+// After each click, it waits up to opts.Timeout for the number of elements in the page to grow, then calls onStep, if not nil.
+// It returns an error if a click fails, onStep fails, or a click loads no new content, which would otherwise loop forever.
+func ClickLoadMore(p *rod.Page, buttonSelector string, opts *RodOptions, onStep func(p *rod.Page) error) error {
+	if p == nil {
+		return fmt.Errorf("rod.Page is nil")
+	}
+	if opts == nil {
+		opts = DefaultRodOptions()
+	}
+	if err := opts.validateSelector(buttonSelector); err != nil {
+		return err
+	}
+	countElements := func(page *rod.Page) (int, error) {
+		res, err := page.Eval(`() => document.getElementsByTagName('*').length`)
+		if err != nil {
+			return 0, fmt.Errorf("failed to count elements: %v", err)
+		}
+		return res.Value.Int(), nil
+	}
+
+	for i := 1; i <= loadMoreMaxClicks; i++ {
+		has, button, err := p.Has(buttonSelector)
+		if err != nil {
+			return fmt.Errorf("failed to check element existence: %s\n%v", buttonSelector, err)
+		}
+		if !has {
+			return nil
+		}
+		res, err := button.Eval(loadMoreAvailableJS)
+		if err != nil {
+			return fmt.Errorf("failed to check load more button: %s\n%v", buttonSelector, err)
+		}
+		if !res.Value.Bool() {
+			return nil
+		}
+
+		before, err := countElements(p)
+		if err != nil {
+			return err
+		}
+		err = withMouse(p, func() error {
+			return button.Click(proto.InputMouseButtonLeft, 1)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to click load more button: %s\n%v", buttonSelector, err)
+		}
+
+		// Timeout context
+		ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
+		err = pollUntil(ctx, opts.RetryDelay, func() (bool, error) {
+			after, err := countElements(p.Context(ctx))
+			return after > before, err
+		})
+		cancel()
+		if err != nil {
+			return fmt.Errorf("load more click %d loaded no new content: %s\n%w", i, buttonSelector, err)
+		}
+
+		if onStep != nil {
+			if err := onStep(p); err != nil {
+				return fmt.Errorf("step %d failed: %w", i, err)
+			}
+		}
+	}
+	return nil
 }
@@ -0,0 +1,37 @@
+package rodutils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-rod/rod"
+)
+
+func TestLockMouseSerializesTarget(t *testing.T) {
+	page := &rod.Page{TargetID: "target-1"}
+	frame := &rod.Page{TargetID: "target-1"}
+	other := &rod.Page{TargetID: "target-2"}
+
+	unlock := lockMouse(page)
+
+	// Other targets have their own lock
+	lockMouse(other)()
+
+	acquired := make(chan struct{})
+	go func() {
+		defer close(acquired)
+		lockMouse(frame)()
+	}()
+	select {
+	case <-acquired:
+		t.Fatal("a page of the same target took the mouse lock while it was held")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	unlock()
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		t.Fatal("the mouse lock was not released")
+	}
+}
@@ -0,0 +1,206 @@
+package rodutils
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// retryAttempts calls attempt up to opts.RetryCount+1 times, each under its own opts.Timeout context, until it succeeds.
+// It waits opts.RetryDelay between attempts and stops early when opts.RetryIf rejects the last error.
+// It returns the result of the first successful attempt, or the error of the last attempt.
+func retryAttempts[T any](opts *RodOptions, attempt func(ctx context.Context) (T, error)) (T, error) {
+	var zero T
+	var lastErr error
+
+	for i := 0; i <= opts.RetryCount; i++ {
+
+		// If an error occurs, wait a bit and then retry
+		if i > 0 {
+			if !opts.shouldRetry(lastErr) {
+				break
+			}
+			time.Sleep(opts.RetryDelay)
+		}
+
+		// Timeout context
+		ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
+		result, err := attempt(ctx)
+		cancel()
+		if err == nil {
+			return result, nil
+		}
+		lastErr = err
+	}
+
+	return zero, lastErr
+}
+
+// retryElement runs the element lookup with retryAttempts and detaches the element it finds from the attempt context.
+// Each attempt context is cancelled as soon as the attempt returns, so the element is rebound to the page context to stay usable.
+// It returns the element of the first successful attempt, or the error of the last attempt.
+func retryElement(p *rod.Page, opts *RodOptions, attempt func(ctx context.Context) (*rod.Element, error)) (*rod.Element, error) {
+	element, err := retryAttempts(opts, attempt)
+	if err != nil {
+		return nil, err
+	}
+	return element.Context(p.GetContext()), nil
+}
+
+// RetryFlow runs the flow on a fresh page and retries it on a brand-new page if it fails.
+// Every page is closed once its attempt ends, so no intermediate state leaks between attempts.
+// Retries are limited by opts.RetryCount and the delay doubles after each attempt, starting at opts.RetryDelay.
+// A screenshot of each failed attempt is saved under DefaultScreenshotPath.
+// It returns an error wrapping the last failure if every attempt fails.
+func RetryFlow(browser *rod.Browser, opts *RodOptions, flow func(p *rod.Page) error) error {
+	if browser == nil {
+		return fmt.Errorf("rod.Browser is nil")
+	}
+	if opts == nil {
+		opts = DefaultRodOptions()
+	}
+	var lastErr error
+	delay := opts.RetryDelay
+
+	for i := 0; i <= opts.RetryCount; i++ {
+
+		// If an error occurs, wait a bit and then retry
+		if i > 0 {
+			if !opts.shouldRetry(lastErr) {
+				break
+			}
+			time.Sleep(delay)
+			delay *= 2
+		}
+
+		page, err := browser.Page(proto.TargetCreateTarget{URL: "about:blank"})
+		if err != nil {
+			lastErr = fmt.Errorf("failed to open page: %w", err)
+			continue
+		}
+
+		err = flow(page)
+		if err == nil {
+			_ = page.Close()
+			return nil
+		}
+		lastErr = err
+
+		// Keep a screenshot of the failed attempt for debugging
+		timestamp := time.Now().Format("2006-01-02_15-04-05")
+		screenshotName := fmt.Sprintf("%s/retry-flow_%d_%s.png", DefaultScreenshotPath, i+1, timestamp)
+		_ = captureScreenshot(page, screenshotName)
+		_ = page.Close()
+	}
+
+	return fmt.Errorf("all flow attempts failed: %w", lastErr)
+}
+
+// MinDwell sleeps until at least minimum has passed since the given time.
+// It returns immediately if that time has already passed.
+func MinDwell(since time.Time, minimum time.Duration) {
+	if remaining := minimum - time.Since(since); remaining > 0 {
+		time.Sleep(remaining)
+	}
+}
+
+// PaceAction sleeps for a random duration between minDelay and maxDelay, then invokes the action.
+// It gives interactions a human-like pace and composes with the Safe* helpers, for example by wrapping a SafeClick call.
+// It returns the error of the action.
+func PaceAction(minDelay, maxDelay time.Duration, action func() error) error {
+	time.Sleep(randomDelay(minDelay, maxDelay))
+	return action()
+}
+
+// CircuitBreaker stops calling a failing target for a while after repeated failures.
+// Share one breaker across many calls, such as SafeClick or Navigate invocations against the same site,
+// so retries back off globally instead of per call. It is safe for concurrent use.
+type CircuitBreaker struct {
+	mu         sync.Mutex
+	threshold  int           // Consecutive failures that open the circuit
+	cooldown   time.Duration // Time the circuit stays open before a trial call
+	failures   int
+	openedAt   time.Time
+	open       bool
+	trial      bool   // Whether a trial call is in flight while half-open
+	generation uint64 // Incremented each time the circuit opens, to tell stale results apart
+}
+
+// circuitTicket identifies a call let through by CircuitBreaker.allow.
+type circuitTicket struct {
+	generation uint64 // Generation of the circuit when the call was let through
+	trial      bool   // Whether the call is the half-open trial call
+}
+
+// NewCircuitBreaker returns a breaker that opens after threshold consecutive failures and lets a trial call through after cooldown.
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{threshold: max(threshold, 1), cooldown: cooldown}
+}
+
+// Do calls fn unless the circuit is open.
+// While open, it returns ErrCircuitOpen without calling fn until the cooldown has passed.
+// After the cooldown, a single trial call is let through: success closes the circuit, failure opens it for another cooldown.
+// It returns the error of fn otherwise.
+func (cb *CircuitBreaker) Do(fn func() error) error {
+	ticket, err := cb.allow()
+	if err != nil {
+		return err
+	}
+	err = fn()
+	cb.record(ticket, err)
+	return err
+}
+
+// allow reports whether a call may proceed, reserving the trial call when the circuit is half-open.
+// It returns the ticket to pass to record with the result of the call.
+func (cb *CircuitBreaker) allow() (circuitTicket, error) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	if !cb.open {
+		return circuitTicket{generation: cb.generation}, nil
+	}
+	if cb.trial || time.Since(cb.openedAt) < cb.cooldown {
+		return circuitTicket{}, ErrCircuitOpen
+	}
+	cb.trial = true
+	return circuitTicket{generation: cb.generation, trial: true}, nil
+}
+
+// record updates the state of the circuit with the result of the call identified by the ticket.
+// Only the trial call decides whether a half-open circuit closes, and results of calls let through before the circuit last opened are ignored.
+func (cb *CircuitBreaker) record(ticket circuitTicket, err error) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	if ticket.trial {
+		cb.trial = false
+		if err == nil {
+			cb.failures = 0
+			cb.open = false
+			return
+		}
+		cb.trip()
+		return
+	}
+	if ticket.generation != cb.generation {
+		return
+	}
+	if err == nil {
+		cb.failures = 0
+		return
+	}
+	cb.failures++
+	if cb.failures >= cb.threshold {
+		cb.trip()
+	}
+}
+
+// trip opens the circuit for another cooldown and starts a new generation.
+func (cb *CircuitBreaker) trip() {
+	cb.open = true
+	cb.openedAt = time.Now()
+	cb.generation++
+}
@@ -0,0 +1,173 @@
+package rodutils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-rod/rod"
+)
+
+func testRetryOptions() *RodOptions {
+	opts := DefaultRodOptions()
+	opts.RetryDelay = time.Millisecond
+	return opts
+}
+
+func TestRetryAttemptsFailsThenSucceeds(t *testing.T) {
+	opts := testRetryOptions()
+	calls := 0
+	got, err := retryAttempts(opts, func(ctx context.Context) (string, error) {
+		calls++
+		if calls < 3 {
+			return "", errors.New("element not found")
+		}
+		return "found", nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error after a successful attempt: %v", err)
+	}
+	if got != "found" {
+		t.Fatalf("got %q, want %q", got, "found")
+	}
+	if calls != 3 {
+		t.Fatalf("got %d attempts, want 3", calls)
+	}
+}
+
+func TestRetryAttemptsReturnsLastError(t *testing.T) {
+	opts := testRetryOptions()
+	opts.RetryCount = 2
+	calls := 0
+	_, err := retryAttempts(opts, func(ctx context.Context) (int, error) {
+		calls++
+		return 0, fmt.Errorf("attempt %d", calls)
+	})
+	if err == nil || err.Error() != "attempt 3" {
+		t.Fatalf("got error %v, want the error of the last attempt", err)
+	}
+	if calls != opts.RetryCount+1 {
+		t.Fatalf("got %d attempts, want %d", calls, opts.RetryCount+1)
+	}
+}
+
+func TestRetryAttemptsStopsWhenRetryIfRejects(t *testing.T) {
+	opts := testRetryOptions()
+	fatal := errors.New("fatal")
+	opts.RetryIf = func(err error) bool { return !errors.Is(err, fatal) }
+	calls := 0
+	_, err := retryAttempts(opts, func(ctx context.Context) (int, error) {
+		calls++
+		return 0, fatal
+	})
+	if !errors.Is(err, fatal) {
+		t.Fatalf("got error %v, want %v", err, fatal)
+	}
+	if calls != 1 {
+		t.Fatalf("got %d attempts, want 1", calls)
+	}
+}
+
+func TestRetryAttemptsUsesFreshTimeoutPerAttempt(t *testing.T) {
+	opts := testRetryOptions()
+	opts.Timeout = 20 * time.Millisecond
+	calls := 0
+	_, err := retryAttempts(opts, func(ctx context.Context) (int, error) {
+		calls++
+		if calls == 1 {
+			<-ctx.Done()
+			return 0, ctx.Err()
+		}
+		if ctx.Err() != nil {
+			t.Fatalf("attempt %d started with a done context", calls)
+		}
+		return calls, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("got %d attempts, want 2", calls)
+	}
+}
+
+func TestRetryElementDetachesFromAttemptContext(t *testing.T) {
+	opts := testRetryOptions()
+	page := &rod.Page{}
+	var attemptCtx context.Context
+	el, err := retryElement(page, opts, func(ctx context.Context) (*rod.Element, error) {
+		attemptCtx = ctx
+		return (&rod.Element{}).Context(ctx), nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if attemptCtx.Err() == nil {
+		t.Fatal("the attempt context was not cancelled after the attempt")
+	}
+	if el.GetContext() == attemptCtx || el.GetContext() != page.GetContext() {
+		t.Fatal("the element is still bound to the attempt context instead of the page context")
+	}
+}
+
+func TestCircuitBreakerOnlyTrialClosesCircuit(t *testing.T) {
+	cb := NewCircuitBreaker(1, time.Millisecond)
+	failure := errors.New("failure")
+
+	// A call let through while closed that finishes after the circuit opens
+	releaseStale := make(chan struct{})
+	staleStarted := make(chan struct{})
+	staleDone := make(chan error)
+	go func() {
+		staleDone <- cb.Do(func() error {
+			close(staleStarted)
+			<-releaseStale
+			return nil
+		})
+	}()
+	<-staleStarted
+
+	if err := cb.Do(func() error { return failure }); !errors.Is(err, failure) {
+		t.Fatalf("got error %v, want %v", err, failure)
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	// The trial call is in flight while the stale call reports success
+	releaseTrial := make(chan struct{})
+	trialStarted := make(chan struct{})
+	trialDone := make(chan error)
+	go func() {
+		trialDone <- cb.Do(func() error {
+			close(trialStarted)
+			<-releaseTrial
+			return failure
+		})
+	}()
+	<-trialStarted
+	close(releaseStale)
+	if err := <-staleDone; err != nil {
+		t.Fatalf("unexpected error from the stale call: %v", err)
+	}
+
+	if err := cb.Do(func() error { return nil }); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("a second call was let through during the trial: %v", err)
+	}
+
+	close(releaseTrial)
+	if err := <-trialDone; !errors.Is(err, failure) {
+		t.Fatalf("got trial error %v, want %v", err, failure)
+	}
+	if err := cb.Do(func() error { return nil }); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("a failed trial did not reopen the circuit: %v", err)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	if err := cb.Do(func() error { return nil }); err != nil {
+		t.Fatalf("trial call after the cooldown failed: %v", err)
+	}
+	if err := cb.Do(func() error { return nil }); err != nil {
+		t.Fatalf("a successful trial did not close the circuit: %v", err)
+	}
+}
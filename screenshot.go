@@ -0,0 +1,322 @@
+package rodutils
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"io"
+	"os"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// ScreenshotDiff compares a full-page screenshot of the page with the baseline PNG at baselinePath.
+// It returns the ratio of differing pixels, whether the ratio exceeds threshold, and an error, if any.
+// Pixels outside the overlapping area of differently sized images count as differing.
+// If the baseline does not exist, the current screenshot is saved as the baseline and differ is false.
+func ScreenshotDiff(page *rod.Page, baselinePath string, threshold float64) (float64, bool, error) {
+	if page == nil {
+		return 0, false, fmt.Errorf("rod.Page is nil")
+	}
+	data, err := page.Screenshot(true, &proto.PageCaptureScreenshot{Format: proto.PageCaptureScreenshotFormatPng})
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to capture screenshot: %w", err)
+	}
+
+	baselineData, err := os.ReadFile(baselinePath)
+	if errors.Is(err, os.ErrNotExist) {
+		if err := writeFile(baselinePath, data); err != nil {
+			return 0, false, fmt.Errorf("failed to save baseline: %w", err)
+		}
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to read baseline: %w", err)
+	}
+
+	current, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to decode screenshot: %w", err)
+	}
+	baseline, err := png.Decode(bytes.NewReader(baselineData))
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to decode baseline: %s\n%w", baselinePath, err)
+	}
+
+	ratio := pixelDiffRatio(current, baseline)
+	return ratio, ratio > threshold, nil
+}
+
+// pixelDiffRatio returns the ratio of pixels that differ between the two images.
+func pixelDiffRatio(a, b image.Image) float64 {
+	ab, bb := a.Bounds(), b.Bounds()
+	width := max(ab.Dx(), bb.Dx())
+	height := max(ab.Dy(), bb.Dy())
+	total := width * height
+	if total == 0 {
+		return 0
+	}
+
+	overlapWidth := min(ab.Dx(), bb.Dx())
+	overlapHeight := min(ab.Dy(), bb.Dy())
+	diff := total - overlapWidth*overlapHeight
+	for y := 0; y < overlapHeight; y++ {
+		for x := 0; x < overlapWidth; x++ {
+			r1, g1, b1, a1 := a.At(ab.Min.X+x, ab.Min.Y+y).RGBA()
+			r2, g2, b2, a2 := b.At(bb.Min.X+x, bb.Min.Y+y).RGBA()
+			if r1 != r2 || g1 != g2 || b1 != b2 || a1 != a2 {
+				diff++
+			}
+		}
+	}
+	return float64(diff) / float64(total)
+}
+
+// highlightAttr marks the element outlined by ScreenshotHighlight.
+const highlightAttr = "data-rodutils-highlight"
+
+// ScreenshotHighlight captures a full-page screenshot with the element matching the selector outlined.
+// The outline is injected as a temporary style and removed again after the capture, even if the capture fails.
+// It creates the parent directory of the path if it does not exist.
+// It returns an error if the element is not found or if the screenshot cannot be captured or saved.
+func ScreenshotHighlight(page *rod.Page, selector, path string) error {
+	if page == nil {
+		return fmt.Errorf("rod.Page is nil")
+	}
+	has, elem, err := page.Has(selector)
+	if err != nil {
+		return fmt.Errorf("failed to check element existence: %s\n%v", selector, err)
+	}
+	if !has {
+		return fmt.Errorf("element not found: %s", selector)
+	}
+
+	_, err = elem.Eval(`(attr) => {
+		this.setAttribute(attr, '');
+		const style = document.createElement('style');
+		style.setAttribute(attr + '-style', '');
+		style.textContent = '[' + attr + '] { outline: 3px solid red !important; outline-offset: 2px !important; }';
+		document.head.appendChild(style);
+	}`, highlightAttr)
+	if err != nil {
+		return fmt.Errorf("failed to highlight element: %s\n%v", selector, err)
+	}
+	defer func() {
+		_, _ = elem.Eval(`(attr) => {
+			this.removeAttribute(attr);
+			document.querySelectorAll('[' + attr + '-style]').forEach(s => s.remove());
+		}`, highlightAttr)
+	}()
+
+	data, err := page.Screenshot(true, &proto.PageCaptureScreenshot{Format: proto.PageCaptureScreenshotFormatPng})
+	if err != nil {
+		return fmt.Errorf("failed to capture screenshot: %w", err)
+	}
+	return writeFile(path, data)
+}
+
+// ScreenshotRegion captures the given rectangle of the page, in CSS pixels relative to the top-left of the document.
+// It creates the parent directory of the path if it does not exist.
+// It returns an error if the region is empty or exceeds the page dimensions, or if the screenshot cannot be captured or saved.
+func ScreenshotRegion(page *rod.Page, x, y, width, height float64, path string) error {
+	if page == nil {
+		return fmt.Errorf("rod.Page is nil")
+	}
+	if x < 0 || y < 0 || width <= 0 || height <= 0 {
+		return fmt.Errorf("invalid screenshot region: x=%v y=%v width=%v height=%v", x, y, width, height)
+	}
+	res, err := page.Eval(`() => ({ w: document.documentElement.scrollWidth, h: document.documentElement.scrollHeight })`)
+	if err != nil {
+		return fmt.Errorf("failed to get page dimensions: %v", err)
+	}
+	pageWidth, pageHeight := res.Value.Get("w").Num(), res.Value.Get("h").Num()
+	if x+width > pageWidth || y+height > pageHeight {
+		return fmt.Errorf("screenshot region exceeds page dimensions %vx%v: x=%v y=%v width=%v height=%v",
+			pageWidth, pageHeight, x, y, width, height)
+	}
+
+	data, err := page.Screenshot(false, &proto.PageCaptureScreenshot{
+		Format:                proto.PageCaptureScreenshotFormatPng,
+		Clip:                  &proto.PageViewport{X: x, Y: y, Width: width, Height: height, Scale: 1},
+		CaptureBeyondViewport: true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to capture screenshot: %w", err)
+	}
+	return writeFile(path, data)
+}
+
+// SaveMHTML saves the page as a single self-contained MHTML file, including its resources.
+// It creates the parent directory of the path if it does not exist.
+// It returns an error if the snapshot cannot be captured or the file cannot be written.
+func SaveMHTML(page *rod.Page, path string) error {
+	if page == nil {
+		return fmt.Errorf("rod.Page is nil")
+	}
+	res, err := proto.PageCaptureSnapshot{Format: proto.PageCaptureSnapshotFormatMhtml}.Call(page)
+	if err != nil {
+		return fmt.Errorf("failed to capture MHTML snapshot: %w", err)
+	}
+	if err := writeFile(path, []byte(res.Data)); err != nil {
+		return fmt.Errorf("failed to save MHTML snapshot: %w", err)
+	}
+	return nil
+}
+
+// DumpHTML saves the current HTML of the page's document, such as the DOM present when a selector failed.
+// It creates the parent directory of the path if it does not exist.
+// It returns an error if the HTML cannot be read or the file cannot be written.
+func DumpHTML(page *rod.Page, path string) error {
+	if page == nil {
+		return fmt.Errorf("rod.Page is nil")
+	}
+	html, err := page.HTML()
+	if err != nil {
+		return fmt.Errorf("failed to get page HTML: %w", err)
+	}
+	if err := writeFile(path, []byte(html)); err != nil {
+		return fmt.Errorf("failed to save page HTML: %w", err)
+	}
+	return nil
+}
+
+// WaitAndScreenshotElement retrieves an element safely and captures a PNG screenshot of just that element.
+// The element is resolved with the retry loop of SafeElement, so the visibility and stability checks of opts apply.
+// It creates the parent directory of the path if it does not exist.
+// It returns an error if the element is not found or if the screenshot cannot be captured or saved.
+func WaitAndScreenshotElement(page *rod.Page, selector, path string, opts *RodOptions) error {
+	elem, err := SafeElement(page, selector, opts)
+	if err != nil {
+		return err
+	}
+	data, err := elem.Screenshot(proto.PageCaptureScreenshotFormatPng, 0)
+	if err != nil {
+		return fmt.Errorf("failed to capture element screenshot: %s\n%w", selector, err)
+	}
+	return writeFile(path, data)
+}
+
+// ScreenshotTo captures a full-page screenshot in the given format and writes it to w.
+// It avoids temporary files when the screenshot is streamed elsewhere, such as to an HTTP response.
+// It returns an error if the screenshot cannot be captured or written.
+func ScreenshotTo(page *rod.Page, w io.Writer, format proto.PageCaptureScreenshotFormat) error {
+	if page == nil {
+		return fmt.Errorf("rod.Page is nil")
+	}
+	if w == nil {
+		return fmt.Errorf("io.Writer is nil")
+	}
+	data, err := page.Screenshot(true, &proto.PageCaptureScreenshot{Format: format})
+	if err != nil {
+		return fmt.Errorf("failed to capture screenshot: %w", err)
+	}
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("failed to write screenshot: %w", err)
+	}
+	return nil
+}
+
+// hideFixedAttr marks the fixed and sticky elements hidden by TiledScreenshot after the first tile.
+const hideFixedAttr = "data-rodutils-hide-fixed"
+
+// TiledScreenshot captures a page taller than the browser's screenshot limit by scrolling through it in tiles,
+// then stitches the tiles into a single PNG written to path.
+// Tiles never overlap, since each one is clipped to its exact document offset, and the last one is cut to the remaining height.
+// Fixed and sticky elements, such as headers, are hidden after the first tile so they do not repeat, and restored afterwards.
+// A tileHeight larger than the viewport is reduced to the viewport height.
+// It returns an error if a tile cannot be captured or the image cannot be encoded or saved.
+func TiledScreenshot(page *rod.Page, path string, tileHeight int) error {
+	if page == nil {
+		return fmt.Errorf("rod.Page is nil")
+	}
+	if tileHeight <= 0 {
+		return fmt.Errorf("tile height must be positive: %d", tileHeight)
+	}
+	res, err := page.Eval(`() => ({
+		width: document.documentElement.clientWidth,
+		height: document.documentElement.scrollHeight,
+		viewport: window.innerHeight,
+		x: window.scrollX,
+		y: window.scrollY,
+	})`)
+	if err != nil {
+		return fmt.Errorf("failed to get page dimensions: %v", err)
+	}
+	width := res.Value.Get("width").Num()
+	total := res.Value.Get("height").Num()
+	step := min(float64(tileHeight), res.Value.Get("viewport").Num())
+	defer func() {
+		_, _ = page.Eval(`(attr, x, y) => {
+			document.querySelectorAll('[' + attr + ']').forEach(el => {
+				el.style.visibility = el.getAttribute(attr);
+				el.removeAttribute(attr);
+			});
+			window.scrollTo(x, y);
+		}`, hideFixedAttr, res.Value.Get("x").Num(), res.Value.Get("y").Num())
+	}()
+
+	var tiles []image.Image
+	for y := 0.0; y < total; y += step {
+		if y > 0 {
+			// Hide fixed and sticky elements once the first tile has captured them
+			_, err = page.Eval(`(attr) => {
+				for (const el of document.querySelectorAll('body *')) {
+					if (el.hasAttribute(attr)) continue;
+					const position = getComputedStyle(el).position;
+					if (position !== 'fixed' && position !== 'sticky') continue;
+					el.setAttribute(attr, el.style.visibility);
+					el.style.visibility = 'hidden';
+				}
+			}`, hideFixedAttr)
+			if err != nil {
+				return fmt.Errorf("failed to hide fixed elements: %v", err)
+			}
+		}
+		_, err = page.Eval(`(y) => new Promise(resolve => {
+			window.scrollTo(0, y);
+			requestAnimationFrame(() => requestAnimationFrame(resolve));
+		})`, y)
+		if err != nil {
+			return fmt.Errorf("failed to scroll to tile: %v", err)
+		}
+
+		data, err := page.Screenshot(false, &proto.PageCaptureScreenshot{
+			Format: proto.PageCaptureScreenshotFormatPng,
+			Clip:   &proto.PageViewport{X: 0, Y: y, Width: width, Height: min(step, total-y), Scale: 1},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to capture tile at %v: %w", y, err)
+		}
+		tile, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			return fmt.Errorf("failed to decode tile at %v: %w", y, err)
+		}
+		tiles = append(tiles, tile)
+	}
+
+	return writeFile(path, stitchTiles(tiles))
+}
+
+// stitchTiles stacks the tiles vertically and encodes the result as PNG.
+func stitchTiles(tiles []image.Image) []byte {
+	width, height := 0, 0
+	for _, tile := range tiles {
+		width = max(width, tile.Bounds().Dx())
+		height += tile.Bounds().Dy()
+	}
+	canvas := image.NewRGBA(image.Rect(0, 0, width, height))
+	y := 0
+	for _, tile := range tiles {
+		b := tile.Bounds()
+		draw.Draw(canvas, image.Rect(0, y, b.Dx(), y+b.Dy()), tile, b.Min, draw.Src)
+		y += b.Dy()
+	}
+	var buf bytes.Buffer
+	// Encoding an in-memory RGBA image into a buffer cannot fail
+	_ = png.Encode(&buf, canvas)
+	return buf.Bytes()
+}
@@ -0,0 +1,109 @@
+package rodutils
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// ValidateSelector checks the syntax of the CSS selector without a browser round-trip.
+// It catches the common typos, such as unbalanced brackets, parentheses or quotes, empty selector lists,
+// dangling or doubled combinators, and class, ID or pseudo-class markers without a name.
+// It does not check that pseudo-classes or attribute operators exist, so a selector passing the check may still be rejected by the browser.
+// It returns an error describing the first problem found.
+func ValidateSelector(selector string) error {
+	if strings.TrimSpace(selector) == "" {
+		return fmt.Errorf("invalid selector: selector is empty")
+	}
+
+	var closers []rune
+	var quote rune
+	escaped := false
+	start := 0
+	var prev rune // Last non-space rune outside quotes and attribute selectors
+	for i, c := range selector {
+		inAttribute := len(closers) > 0 && closers[len(closers)-1] == ']'
+		switch {
+		case escaped:
+			escaped = false
+		case c == '\\':
+			escaped = true
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[':
+			if strings.HasPrefix(strings.TrimSpace(selector[i+1:]), "]") {
+				return fmt.Errorf("invalid selector: empty attribute selector at position %d: %s", i, selector)
+			}
+			closers = append(closers, ']')
+		case c == '(':
+			closers = append(closers, ')')
+		case c == ']' || c == ')':
+			if len(closers) == 0 || closers[len(closers)-1] != c {
+				return fmt.Errorf("invalid selector: unexpected %q at position %d: %s", c, i, selector)
+			}
+			closers = closers[:len(closers)-1]
+		case c == ',' && len(closers) == 0:
+			if err := validateCompound(selector[start:i], selector); err != nil {
+				return err
+			}
+			start = i + 1
+		case isCombinator(c) && !inAttribute:
+			if isCombinator(prev) {
+				return fmt.Errorf("invalid selector: %q directly follows combinator %q at position %d: %s", c, prev, i, selector)
+			}
+		case (c == '.' || c == '#' || c == ':') && len(closers) == 0:
+			if !startsName(selector[i+1:], c == ':') {
+				return fmt.Errorf("invalid selector: %q not followed by a valid name at position %d: %s", c, i, selector)
+			}
+		}
+		if quote == 0 && !inAttribute && !unicode.IsSpace(c) {
+			prev = c
+		}
+	}
+	if escaped {
+		return fmt.Errorf("invalid selector: trailing escape: %s", selector)
+	}
+	if quote != 0 {
+		return fmt.Errorf("invalid selector: unclosed %c quote: %s", quote, selector)
+	}
+	if len(closers) > 0 {
+		return fmt.Errorf("invalid selector: missing %q: %s", closers[len(closers)-1], selector)
+	}
+	return validateCompound(selector[start:], selector)
+}
+
+// validateCompound checks one selector of a top-level selector list.
+// It returns an error if the part is empty or starts or ends with a combinator.
+func validateCompound(part, selector string) error {
+	part = strings.TrimSpace(part)
+	if part == "" {
+		return fmt.Errorf("invalid selector: empty selector in list: %s", selector)
+	}
+	if isCombinator(rune(part[0])) || isCombinator(rune(part[len(part)-1])) {
+		return fmt.Errorf("invalid selector: dangling combinator in %q: %s", part, selector)
+	}
+	return nil
+}
+
+// startsName reports whether rest starts with an identifier, as required after a class, ID or pseudo-class marker.
+// Pseudo-classes may also be followed by a second colon for pseudo-elements.
+func startsName(rest string, pseudo bool) bool {
+	if pseudo && strings.HasPrefix(rest, ":") {
+		rest = rest[1:]
+	}
+	if rest == "" {
+		return false
+	}
+	c := rest[0]
+	return c == '-' || c == '_' || c == '\\' || c >= 0x80 ||
+		(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// isCombinator reports whether c is one of the explicit combinators, child, next-sibling or subsequent-sibling.
+func isCombinator(c rune) bool {
+	return c == '>' || c == '+' || c == '~'
+}
@@ -0,0 +1,63 @@
+package rodutils
+
+import "testing"
+
+func TestValidateSelector(t *testing.T) {
+	valid := []string{
+		"div.card > p",
+		`a[href$=".pdf"]`,
+		`a[title='x]'] b`,
+		`[class~="active"] + li`,
+		"li:nth-child(2n+1)::before",
+		"div:has(> img)",
+		"#main, .item ~ .item",
+		`input\:focus`,
+	}
+	for _, selector := range valid {
+		if err := ValidateSelector(selector); err != nil {
+			t.Errorf("ValidateSelector(%q) = %v, want nil", selector, err)
+		}
+	}
+
+	invalid := []string{
+		"",
+		"   ",
+		"div[",
+		"div]",
+		"a(",
+		`a[title="x]`,
+		"a,",
+		", a",
+		"a >",
+		"> a",
+		"a > > b",
+		"a >> b",
+		"a + ~ b",
+		"div:has(> > img)",
+		".",
+		"div.",
+		"p:",
+		"#1a",
+		"[ ]",
+		`a\`,
+	}
+	for _, selector := range invalid {
+		if err := ValidateSelector(selector); err == nil {
+			t.Errorf("ValidateSelector(%q) = nil, want an error", selector)
+		}
+	}
+}
+
+func TestValidateSelectorsOption(t *testing.T) {
+	opts := DefaultRodOptions()
+	if err := opts.validateSelector("div["); err != nil {
+		t.Fatalf("selectors were validated while ValidateSelectors is off: %v", err)
+	}
+	opts.ValidateSelectors = true
+	if err := opts.validateSelector("div", "div["); err == nil {
+		t.Fatal("an invalid selector passed with ValidateSelectors on")
+	}
+	if err := opts.validateSelector("div", ".item"); err != nil {
+		t.Fatalf("unexpected error for valid selectors: %v", err)
+	}
+}
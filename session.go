@@ -0,0 +1,150 @@
+package rodutils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// WaitCookie waits for a cookie with the given name to be set for the page's current URL.
+// A cookie with an empty value is treated as not set yet, since logouts often clear the value instead of deleting the cookie.
+// It returns the cookie and an error, if any.
+// If the cookie is not set within opts.Timeout, it returns an error.
+func WaitCookie(page *rod.Page, name string, opts *RodOptions) (*proto.NetworkCookie, error) {
+	if page == nil {
+		return nil, fmt.Errorf("rod.Page is nil")
+	}
+	if opts == nil {
+		opts = DefaultRodOptions()
+	}
+
+	// Timeout context
+	ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
+	defer cancel()
+
+	var cookie *proto.NetworkCookie
+	err := pollUntil(ctx, opts.RetryDelay, func() (bool, error) {
+		cookies, err := page.Context(ctx).Cookies(nil)
+		if err != nil {
+			return false, fmt.Errorf("failed to get cookies: %v", err)
+		}
+		for _, c := range cookies {
+			if c.Name == name && c.Value != "" {
+				cookie = c
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cookie not set: %s\n%w", name, err)
+	}
+	return cookie, nil
+}
+
+// sessionFormatVersion is the version of the on-disk format written by SaveSession.
+// Bump it when the format changes, and keep LoadSession able to read older versions.
+const sessionFormatVersion = 1
+
+// sessionSnapshot is the on-disk format of a browsing session.
+type sessionSnapshot struct {
+	Version        int                    `json:"version"`
+	URL            string                 `json:"url"`
+	Cookies        []*proto.NetworkCookie `json:"cookies"`
+	LocalStorage   map[string]string      `json:"localStorage"`
+	SessionStorage map[string]string      `json:"sessionStorage"`
+}
+
+// storageJS reads the local and session storage of the current origin.
+const storageJS = `() => {
+	const dump = (s) => Object.fromEntries(Array.from({ length: s.length }, (_, i) => [s.key(i), s.getItem(s.key(i))]));
+	return { local: dump(localStorage), session: dump(sessionStorage) };
+}`
+
+// SaveSession saves the cookies, local storage and session storage of the page's current URL to a JSON file.
+// It creates the parent directory of the path if it does not exist.
+// It returns an error if the session cannot be read or the file cannot be written.
+func SaveSession(page *rod.Page, path string) error {
+	if page == nil {
+		return fmt.Errorf("rod.Page is nil")
+	}
+	info, err := page.Info()
+	if err != nil {
+		return fmt.Errorf("failed to get page info: %v", err)
+	}
+	cookies, err := page.Cookies([]string{info.URL})
+	if err != nil {
+		return fmt.Errorf("failed to get cookies: %v", err)
+	}
+	res, err := page.Eval(storageJS)
+	if err != nil {
+		return fmt.Errorf("failed to read storage: %v", err)
+	}
+
+	snapshot := sessionSnapshot{
+		Version:        sessionFormatVersion,
+		URL:            info.URL,
+		Cookies:        cookies,
+		LocalStorage:   map[string]string{},
+		SessionStorage: map[string]string{},
+	}
+	for k, v := range res.Value.Get("local").Map() {
+		snapshot.LocalStorage[k] = v.Str()
+	}
+	for k, v := range res.Value.Get("session").Map() {
+		snapshot.SessionStorage[k] = v.Str()
+	}
+
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode session: %v", err)
+	}
+	return writeFile(path, data)
+}
+
+// LoadSession restores a browsing session saved by SaveSession into the page.
+// It sets the cookies first, navigates to the saved URL, and restores the storage once the page has loaded,
+// since storage is scoped to the origin of the loaded document.
+// It returns an error if the file cannot be read, has an unsupported version, or the session cannot be restored.
+func LoadSession(page *rod.Page, path string) error {
+	if page == nil {
+		return fmt.Errorf("rod.Page is nil")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read session file: %s\n%v", path, err)
+	}
+	var snapshot sessionSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return fmt.Errorf("failed to decode session file: %s\n%v", path, err)
+	}
+	if snapshot.Version < 1 || snapshot.Version > sessionFormatVersion {
+		return fmt.Errorf("unsupported session format version: %d", snapshot.Version)
+	}
+
+	// Cookies must be in place before navigating so the first request is authenticated
+	if len(snapshot.Cookies) > 0 {
+		if err := page.SetCookies(proto.CookiesToParams(snapshot.Cookies)); err != nil {
+			return fmt.Errorf("failed to set cookies: %v", err)
+		}
+	}
+	if _, err := Navigate(page, snapshot.URL); err != nil {
+		return err
+	}
+	if err := page.WaitLoad(); err != nil {
+		return fmt.Errorf("error waiting for page load to complete: %v", err)
+	}
+
+	_, err = page.Eval(`(local, session) => {
+		for (const [k, v] of Object.entries(local || {})) localStorage.setItem(k, v);
+		for (const [k, v] of Object.entries(session || {})) sessionStorage.setItem(k, v);
+	}`, snapshot.LocalStorage, snapshot.SessionStorage)
+	if err != nil {
+		return fmt.Errorf("failed to restore storage: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package rodutils
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// pinchRadius is the initial distance of each touch point from the pinch center, in CSS pixels.
+const pinchRadius = 50.0
+
+// pinchSteps is the number of touch move events dispatched during a pinch.
+const pinchSteps = 10
+
+// touchFrameInterval is the delay between consecutive touch move events.
+const touchFrameInterval = 16 * time.Millisecond
+
+// requireTouch checks that touch emulation is enabled for the page.
+// It returns an error if the page does not report any touch points.
+func requireTouch(page *rod.Page) error {
+	res, err := page.Eval(`() => navigator.maxTouchPoints`)
+	if err != nil {
+		return fmt.Errorf("failed to check touch support: %v", err)
+	}
+	if res.Value.Int() == 0 {
+		return errors.New("touch emulation is not enabled, enable it with proto.EmulationSetTouchEmulationEnabled first")
+	}
+	return nil
+}
+
+// Pinch performs a two-finger pinch gesture centered on the given point.
+// A scale greater than 1 spreads the fingers apart to zoom in, and a scale less than 1 pinches them together to zoom out.
+// It requires touch emulation to be enabled and returns an error if it is not.
+// It returns an error if dispatching a touch event fails.
+func Pinch(page *rod.Page, centerX, centerY float64, scale float64) error {
+	if page == nil {
+		return fmt.Errorf("rod.Page is nil")
+	}
+	if scale <= 0 {
+		return fmt.Errorf("pinch scale must be positive: %v", scale)
+	}
+	if err := requireTouch(page); err != nil {
+		return err
+	}
+
+	points := func(radius float64) []*proto.InputTouchPoint {
+		id0, id1 := 0.0, 1.0
+		return []*proto.InputTouchPoint{
+			{X: centerX - radius, Y: centerY, ID: &id0},
+			{X: centerX + radius, Y: centerY, ID: &id1},
+		}
+	}
+
+	err := proto.InputDispatchTouchEvent{Type: proto.InputDispatchTouchEventTypeTouchStart, TouchPoints: points(pinchRadius)}.Call(page)
+	if err != nil {
+		return fmt.Errorf("failed to start pinch: %v", err)
+	}
+	for i := 1; i <= pinchSteps; i++ {
+		time.Sleep(touchFrameInterval)
+		radius := pinchRadius * (1 + (scale-1)*float64(i)/pinchSteps)
+		err = proto.InputDispatchTouchEvent{Type: proto.InputDispatchTouchEventTypeTouchMove, TouchPoints: points(radius)}.Call(page)
+		if err != nil {
+			return fmt.Errorf("failed to move pinch: %v", err)
+		}
+	}
+	err = proto.InputDispatchTouchEvent{Type: proto.InputDispatchTouchEventTypeTouchEnd, TouchPoints: []*proto.InputTouchPoint{}}.Call(page)
+	if err != nil {
+		return fmt.Errorf("failed to end pinch: %v", err)
+	}
+	return nil
+}
+
+// LongPress touches the center of the element, holds it for duration, then lifts the finger, such as to reveal a mobile context menu.
+// It requires touch emulation to be enabled and returns an error if it is not.
+// It returns an error if the element has no visible shape or dispatching a touch event fails.
+func LongPress(e *rod.Element, duration time.Duration) error {
+	if e == nil {
+		return errors.New("rod.Element is nil")
+	}
+	page := e.Page()
+	if err := requireTouch(page); err != nil {
+		return err
+	}
+	if err := e.ScrollIntoView(); err != nil {
+		return fmt.Errorf("failed to scroll element into view: %v", err)
+	}
+	shape, err := e.Shape()
+	if err != nil {
+		return fmt.Errorf("failed to get element shape: %v", err)
+	}
+	point := shape.OnePointInside()
+	if point == nil {
+		return errors.New("element has no visible shape to press")
+	}
+
+	id := 0.0
+	err = proto.InputDispatchTouchEvent{
+		Type:        proto.InputDispatchTouchEventTypeTouchStart,
+		TouchPoints: []*proto.InputTouchPoint{{X: point.X, Y: point.Y, ID: &id}},
+	}.Call(page)
+	if err != nil {
+		return fmt.Errorf("failed to start long press: %v", err)
+	}
+	time.Sleep(duration)
+	err = proto.InputDispatchTouchEvent{Type: proto.InputDispatchTouchEventTypeTouchEnd, TouchPoints: []*proto.InputTouchPoint{}}.Call(page)
+	if err != nil {
+		return fmt.Errorf("failed to end long press: %v", err)
+	}
+	return nil
+}
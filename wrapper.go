@@ -3,8 +3,10 @@ package rodutils
 import (
 	"context"
 	"fmt"
+	"math/rand/v2"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/go-rod/rod"
@@ -13,77 +15,156 @@ import (
 
 // RodOperationWrapperOptions encapsulates the optional parameters for RodOperationWrapper.
 type RodOperationWrapperOptions struct {
-	TimeoutDuration *time.Duration
+	TimeoutDuration *time.Duration // Operation timeout in seconds, such as 5 for five seconds, as the value is multiplied by time.Second
 	Path            *string
 	Name            *string
+	WaitFonts       *bool // Whether to wait for web fonts to load before capturing the screenshot
+	DumpHTML        *bool // Whether to also save the page HTML next to the screenshot on failure
 }
 
 // RodOperationWrapper wraps a rod operation with error handling and screenshot capture.
 // It executes the given operation and captures a screenshot if an error occurs.
 // It returns an error if the operation fails.
 func RodOperationWrapper(page *rod.Page, operation func() error, opts *RodOperationWrapperOptions) error {
-	var timeout *time.Duration
-	var path *string
-	var name *string
+	settings := resolveWrapperOptions(opts)
+	err := timeLimit(settings.timeout, func() error {
+		return operation()
+	})
+	if err != nil {
+		screenshotName := fmt.Sprintf("%s/%s.png", settings.path, settings.name)
+		if screenErr := settings.screenshot(page, screenshotName); screenErr != nil {
+			return screenErr
+		}
+	}
+	return err
+}
 
-	if opts != nil {
-		timeout = opts.TimeoutDuration
-		path = opts.Path
-		name = opts.Name
+// RodSequence runs the operations in order with the screenshot-on-failure policy of RodOperationWrapper.
+// It stops at the first failing operation and captures one screenshot named after its 1-based step index.
+// Each operation is limited by the wrapper timeout on its own.
+// It returns an error wrapping the original operation error, combined with the screenshot error if the capture also fails.
+func RodSequence(page *rod.Page, opts *RodOperationWrapperOptions, ops ...func() error) error {
+	settings := resolveWrapperOptions(opts)
+	for i, op := range ops {
+		err := timeLimit(settings.timeout, op)
+		if err == nil {
+			continue
+		}
+		stepErr := fmt.Errorf("step %d failed: %w", i+1, err)
+		screenshotName := fmt.Sprintf("%s/%s_step-%d.png", settings.path, settings.name, i+1)
+		if screenErr := settings.screenshot(page, screenshotName); screenErr != nil {
+			return combineErrors([]error{stepErr, screenErr})
+		}
+		return stepErr
 	}
+	return nil
+}
 
-	if path == nil {
-		defaultValue := DefaultScreenshotPath
-		path = &defaultValue
+// WithDeadline runs the functions in order under the shared context, so a single deadline covers the whole group.
+// Bind the page to the context inside each function, for example with page.Context(ctx), for the deadline to apply to its actions.
+// It stops at the first failing function, or before the next one once the context is done.
+// It returns an error naming the 1-based index of the function that failed or was aborted.
+func WithDeadline(ctx context.Context, fns ...func(context.Context) error) error {
+	for i, fn := range fns {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("step %d aborted: %w", i+1, err)
+		}
+		if err := fn(ctx); err != nil {
+			return fmt.Errorf("step %d failed: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// wrapperSettings holds the resolved options of RodOperationWrapper.
+type wrapperSettings struct {
+	timeout   time.Duration
+	path      string
+	name      string
+	waitFonts bool
+	dumpHTML  bool
+}
+
+// resolveWrapperOptions applies the defaults to the wrapper options.
+// The screenshot name is suffixed with the current timestamp.
+func resolveWrapperOptions(opts *RodOperationWrapperOptions) wrapperSettings {
+	settings := wrapperSettings{
+		timeout: DefaultTimeoutDuration,
+		path:    DefaultScreenshotPath,
 	}
 	timestamp := time.Now().Format("2006-01-02_15-04-05")
-	if name == nil {
-		name = &timestamp
-	} else {
-		tmp := fmt.Sprintf("%s_%s", *name, timestamp)
-		name = &tmp
-	}
-	if timeout == nil {
-		defaultValue := DefaultTimeoutDuration
-		timeout = &defaultValue
-	}
-	limit := time.Duration(*timeout) * time.Second
-	err := timeLimit(limit, func() error {
-		return operation()
-	})
-	if err != nil {
-		screenshotName := fmt.Sprintf("%s/%s.png", *path, *name)
-		dir := filepath.Dir(screenshotName)
-		if err := os.MkdirAll(dir, 0755); err != nil {
-			return fmt.Errorf("failed to create screenshot directory: %w", err)
+	settings.name = timestamp
+
+	if opts != nil {
+		if opts.TimeoutDuration != nil {
+			// TimeoutDuration counts seconds, as it always has for RodOperationWrapper callers
+			settings.timeout = *opts.TimeoutDuration * time.Second
 		}
-		screenshotData, screenErr := page.Screenshot(true, &proto.PageCaptureScreenshot{Format: proto.PageCaptureScreenshotFormatPng})
-		if screenErr != nil {
-			return fmt.Errorf("failed to capture screenshot: %w", screenErr)
+		if opts.Path != nil {
+			settings.path = *opts.Path
 		}
-		// Save the screenshot data to a file
-		if fileErr := os.WriteFile(screenshotName, screenshotData, 0644); fileErr != nil {
-			return fmt.Errorf("failed to save screenshot: %v", fileErr)
+		if opts.Name != nil {
+			settings.name = fmt.Sprintf("%s_%s", *opts.Name, timestamp)
 		}
+		settings.waitFonts = opts.WaitFonts != nil && *opts.WaitFonts
+		settings.dumpHTML = opts.DumpHTML != nil && *opts.DumpHTML
 	}
-	return err
+	return settings
+}
+
+// screenshot captures the failure screenshot to screenshotName according to the settings.
+// If enabled, it waits for the web fonts first, and the screenshot is still captured when the fonts do not load.
+// If enabled, the page HTML is saved next to it with the .html extension, even when the screenshot fails.
+// It returns the errors of every step that failed, combined.
+func (s wrapperSettings) screenshot(page *rod.Page, screenshotName string) error {
+	var errs []error
+	if s.waitFonts {
+		errs = append(errs, WaitFontsReady(page, nil))
+	}
+	errs = append(errs, captureScreenshot(page, screenshotName))
+	if s.dumpHTML {
+		htmlName := strings.TrimSuffix(screenshotName, filepath.Ext(screenshotName)) + ".html"
+		errs = append(errs, DumpHTML(page, htmlName))
+	}
+	return combineErrors(errs)
+}
+
+// captureScreenshot captures a full-page PNG screenshot and saves it to the file at screenshotName.
+// It creates the parent directory if needed.
+// It returns an error if the directory cannot be created or the screenshot cannot be captured or saved.
+func captureScreenshot(page *rod.Page, screenshotName string) error {
+	dir := filepath.Dir(screenshotName)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create screenshot directory: %w", err)
+	}
+	file, fileErr := os.OpenFile(screenshotName, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if fileErr != nil {
+		return fmt.Errorf("failed to save screenshot: %v", fileErr)
+	}
+	// Stream the screenshot data to the file, and drop the file if the capture fails
+	if screenErr := ScreenshotTo(page, file, proto.PageCaptureScreenshotFormatPng); screenErr != nil {
+		_ = file.Close()
+		_ = os.Remove(screenshotName)
+		return screenErr
+	}
+	if fileErr := file.Close(); fileErr != nil {
+		return fmt.Errorf("failed to save screenshot: %v", fileErr)
+	}
+	return nil
 }
 
 // timeLimit executes the given function with a time limit.
-// It returns an error if the operation takes longer than the given timeout.
+// It returns the result of f as soon as f returns, or an error if the operation takes longer than the given timeout.
+// After a timeout, f keeps running in the background and its result is dropped.
 func timeLimit(timeout time.Duration, f func() error) error {
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
-	// Receive results using channels
-	errorChan := make(chan error)
+	// Receive results using channels, buffered so the goroutine can exit after a timeout
+	errorChan := make(chan error, 1)
 
 	go func() {
-		err := f()
-		if err != nil {
-			errorChan <- err
-			return
-		}
+		errorChan <- f()
 	}()
 
 	select {
@@ -93,3 +174,53 @@ func timeLimit(timeout time.Duration, f func() error) error {
 		return fmt.Errorf("operation timed out: %v", ctx.Err())
 	}
 }
+
+// pollUntil calls cond every interval until it reports true or ctx is done.
+// It returns an error if cond fails or if ctx is done before cond reports true.
+func pollUntil(ctx context.Context, interval time.Duration, cond func() (bool, error)) error {
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		ok, err := cond()
+		if err == nil && ok {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return fmt.Errorf("operation timed out: %w", ctx.Err())
+		}
+		if err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("operation timed out: %w", ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// writeFile writes the data to the file at path, creating the parent directory if needed.
+// It returns an error if the directory cannot be created or the file cannot be written.
+func writeFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write file: %s\n%w", path, err)
+	}
+	return nil
+}
+
+// randomDelay returns a random duration between minDelay and maxDelay, inclusive.
+// If maxDelay is not greater than minDelay, it returns minDelay.
+func randomDelay(minDelay, maxDelay time.Duration) time.Duration {
+	if maxDelay <= minDelay {
+		return minDelay
+	}
+	return minDelay + rand.N(maxDelay-minDelay+1)
+}
@@ -0,0 +1,45 @@
+package rodutils
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTimeLimitReturnsResultBeforeTimeout(t *testing.T) {
+	start := time.Now()
+	if err := timeLimit(time.Second, func() error { return nil }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed >= time.Second {
+		t.Fatalf("timeLimit waited %v for a successful operation", elapsed)
+	}
+
+	want := errors.New("operation failed")
+	if err := timeLimit(time.Second, func() error { return want }); !errors.Is(err, want) {
+		t.Fatalf("got error %v, want %v", err, want)
+	}
+}
+
+func TestTimeLimitTimesOut(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	err := timeLimit(10*time.Millisecond, func() error {
+		<-release
+		return nil
+	})
+	if err == nil {
+		t.Fatal("expected a timeout error")
+	}
+}
+
+func TestResolveWrapperOptionsTimeoutInSeconds(t *testing.T) {
+	if got := resolveWrapperOptions(nil).timeout; got != DefaultTimeoutDuration {
+		t.Fatalf("got default timeout %v, want %v", got, DefaultTimeoutDuration)
+	}
+	seconds := time.Duration(5)
+	got := resolveWrapperOptions(&RodOperationWrapperOptions{TimeoutDuration: &seconds}).timeout
+	if got != 5*time.Second {
+		t.Fatalf("got timeout %v, want %v", got, 5*time.Second)
+	}
+}